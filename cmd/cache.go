@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/service"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Tools for the helm chart snapshots cache",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the cached helm chart snapshots",
+	Long:  `Deletes the services of every helm chart tag cached in the user cache dir, they are loaded from GitHub again when needed`,
+	Args:  cobra.NoArgs,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshots := service.DefaultSnapshots()
+		if snapshots == nil {
+			return errors.New("the snapshots are disabled or there is no user cache dir")
+		}
+		if err := snapshots.Clear(); err != nil {
+			return err
+		}
+		fmt.Println(promptui.IconGood + " Cache cleared")
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
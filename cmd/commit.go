@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/util"
+	"github.com/adam-putland/divido-cli/internal/util/github"
+)
+
+// CommitForm returns the commit details form, the pull request fields are only included for pull requests,
+// whose branch cannot be the base branch
+func CommitForm(c *github.Commit, baseBranch string, pullRequest bool) *util.Form {
+	branch := &util.Field{Label: "Branch", Value: &c.Branch, Validate: util.Required}
+	if pullRequest {
+		branch.Validate = util.All(util.Required, util.NotEqual(baseBranch))
+	}
+
+	fields := []*util.Field{
+		{Label: "Author Name", Value: &c.AuthorName, Validate: util.Required},
+		{Label: "Author Email", Value: &c.AuthorEmail, Validate: util.Email},
+		{Label: "Commit Message", Value: &c.Message, Validate: util.Required},
+		branch,
+	}
+	if pullRequest {
+		fields = append(fields,
+			&util.Field{Label: "Pull Request Title", Value: &c.PullRequestTitle, Validate: util.Required},
+			&util.Field{Label: "Pull Request Description", Value: &c.PullRequestDescription},
+		)
+	}
+
+	return &util.Form{
+		Title:  fmt.Sprintf("Github Details (%s)", c.Org),
+		Fields: fields,
+		Actions: []util.Action{
+			{Label: "Edit in $EDITOR", Run: func() error { return editCommit(c, pullRequest) }},
+		},
+	}
+}
+
+// editCommit opens the commit details in $EDITOR
+func editCommit(c *github.Commit, pullRequest bool) error {
+	content, err := c.EditTemplate(pullRequest)
+	if err != nil {
+		return fmt.Errorf("rendering commit details %w", err)
+	}
+
+	edited, err := util.EditInEditor(content, "commit-*.yaml")
+	if err != nil {
+		return err
+	}
+	return c.ApplyEdit(edited)
+}
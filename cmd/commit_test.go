@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/adam-putland/divido-cli/internal/util/github"
+	"testing"
+)
+
+func TestCommitForm(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		commit      github.Commit
+		pullRequest bool
+		wantFields  int
+		wantErr     bool
+	}{
+		{name: "direct_commit_to_main", commit: github.Commit{AuthorName: "dividotech", AuthorEmail: "tech@divido.com", Branch: "master",
+			Message: "bump"}, pullRequest: false, wantFields: 4, wantErr: false},
+		{name: "pull_request_from_main", commit: github.Commit{AuthorName: "dividotech", AuthorEmail: "tech@divido.com", Branch: "master",
+			Message: "bump", PullRequestTitle: "bump"}, pullRequest: true, wantFields: 6, wantErr: true},
+		{name: "pull_request", commit: github.Commit{AuthorName: "dividotech", AuthorEmail: "tech@divido.com", Branch: "chore/bump",
+			Message: "bump", PullRequestTitle: "bump"}, pullRequest: true, wantFields: 6, wantErr: false},
+		{name: "invalid_email", commit: github.Commit{AuthorName: "dividotech", AuthorEmail: "tech", Branch: "master",
+			Message: "bump"}, pullRequest: false, wantFields: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := CommitForm(&tt.commit, "master", tt.pullRequest)
+			if len(form.Fields) != tt.wantFields {
+				t.Errorf("CommitForm() got = %v fields, want %v", len(form.Fields), tt.wantFields)
+			}
+			if err := form.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"github.com/adam-putland/divido-cli/internal/service"
+	"github.com/adam-putland/divido-cli/internal/util"
+	"github.com/adam-putland/divido-cli/internal/util/github"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const _orgPrompt = "GitHub organisation"
+
+var (
+	skipRepos  bool
+	resolved   bool
+	initOrg    string
+	initOutput string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Tools for the config file",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the config file",
+	Long: `Decodes the config strictly, rejecting unknown fields, validates the platforms, service rules and
+export templates and checks every configured repo is reachable with the current token`,
+	Args: cobra.NoArgs,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := internal.LoadConfig(viper.GetViper()); err != nil {
+			fmt.Println(promptui.IconBad + " Config not valid")
+			return err
+		}
+
+		ctx := context.Background()
+		app := internal.CreateApp(ctx)
+		if app == nil {
+			return errors.New("error generation application")
+		}
+		s, err := getService(*app)
+		if err != nil {
+			return err
+		}
+
+		failed := false
+		for _, err := range s.ValidateExport() {
+			fmt.Printf("%s %s\n", promptui.IconBad, err)
+			failed = true
+		}
+
+		if !skipRepos {
+			for _, check := range s.CheckRepos(ctx) {
+				if check.Err != nil {
+					fmt.Printf("%s %s not reachable %s\n", promptui.IconBad, check.Repo, check.Err)
+					failed = true
+					continue
+				}
+				fmt.Printf("%s %s\n", promptui.IconGood, check.Repo)
+			}
+		}
+
+		if failed {
+			fmt.Println(promptui.IconBad + " Config not valid")
+			return errors.New("config validation failed")
+		}
+		fmt.Println(promptui.IconGood + " Config valid")
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file",
+	Long: `Asks for the GitHub organisation, discovers its helm chart (*-hlm) and environment (*-inf) repos,
+proposes the platforms grouped by prefix and writes the config to $HOME/config.json (or the --output path)`,
+	Args: cobra.NoArgs,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		return ConfigInitUI(ctx)
+	},
+}
+
+func ConfigInitUI(ctx context.Context) error {
+	path := initOutput
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, "config.json")
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		index, _, err := util.Select(fmt.Sprintf("%s already exists, overwrite it?", path), util.Options{"No", "Yes"})
+		if err != nil {
+			return fmt.Errorf(PromptFailedMsg, err)
+		}
+		if index == 0 {
+			return nil
+		}
+	}
+
+	org := initOrg
+	if org == "" {
+		var err error
+		if org, err = util.Prompt(_orgPrompt); err != nil {
+			return fmt.Errorf(PromptFailedMsg, err)
+		}
+	}
+
+	config := &models.Config{Schema: internal.ConfigSchemaURL, Github: models.GithubConfig{Org: org}}
+	s := service.New(github.NewGithubClient(ctx, internal.GithubToken()), config)
+
+	platforms, err := s.DiscoverPlatforms(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(platforms) == 0 {
+		fmt.Printf("No helm chart (*-hlm) repos found in %s, add the platforms to the config manually\n", org)
+	} else {
+		labels := make(util.Options, 0, len(platforms))
+		for _, platform := range platforms {
+			envs := make([]string, 0, len(platform.Envs))
+			for _, env := range platform.Envs {
+				envs = append(envs, env.Name)
+			}
+			labels = append(labels, fmt.Sprintf("%s (%s): %s", platform.Name, platform.HelmChartRepo, strings.Join(envs, ", ")))
+		}
+
+		prompt := util.MultiSelect{
+			Label:    "Select platforms",
+			Items:    labels,
+			Size:     8,
+			HideHelp: false,
+		}
+		selected, err := prompt.Run()
+		if err != nil {
+			return fmt.Errorf(PromptFailedMsg, err)
+		}
+		for _, index := range selected {
+			config.Platforms = append(config.Platforms, platforms[index])
+		}
+	}
+
+	if config.Github.MainBranch, err = util.PromptWithDefault("Main branch", "master"); err != nil {
+		return fmt.Errorf(PromptFailedMsg, err)
+	}
+	if config.Github.AuthorName, err = util.Prompt("Commit author name"); err != nil {
+		return fmt.Errorf(PromptFailedMsg, err)
+	}
+	if config.Github.AuthorEmail, err = util.Prompt("Commit author email"); err != nil {
+		return fmt.Errorf(PromptFailedMsg, err)
+	}
+
+	if err := internal.SaveConfig(path, config); err != nil {
+		return fmt.Errorf("saving config %w", err)
+	}
+	fmt.Printf("%s Config written to %s\n", promptui.IconGood, path)
+	return nil
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the config layers",
+	Long: `Lists the config layers merged in order, later layers override earlier ones.
+With --resolved every effective config value is printed with the layer it came from`,
+	Args: cobra.NoArgs,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Print(configLayers)
+		if resolved {
+			fmt.Println()
+			fmt.Print(configLayers.Resolved(viper.GetViper()))
+		}
+		return nil
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&skipRepos, "skip-repos", false, "skip checking the configured repos are reachable")
+	configCmd.AddCommand(configValidateCmd)
+	configInitCmd.Flags().StringVar(&initOrg, "org", "", "GitHub organisation, skips its prompt")
+	configInitCmd.Flags().StringVarP(&initOutput, "output", "o", "", "config file written (default is $HOME/config.json)")
+	util.DefaultPrompter().Flag(_orgPrompt, "--org")
+	configCmd.AddCommand(configInitCmd)
+	configShowCmd.Flags().BoolVar(&resolved, "resolved", false, "print the effective config and where each value came from")
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/adam-putland/divido-cli/internal/util"
 	"github.com/adam-putland/divido-cli/internal/util/github"
 	"github.com/sarulabs/di"
-	"strings"
 )
 
 var envOptions = util.Options{
@@ -17,8 +16,11 @@ var envOptions = util.Options{
 	"Update Services via overrides (In development)",
 }
 
-func EnvUI(ctx context.Context, app di.Container) error {
-	s := app.Get("service").(*service.Service)
+func EnvUI(ctx context.Context, app di.Container, nav *util.Navigator) error {
+	s, err := getService(app)
+	if err != nil {
+		return err
+	}
 	cfg := s.GetConfig()
 	platIndex, _, err := util.Select("Select platform", cfg.ListPlatform())
 	if err != nil {
@@ -37,14 +39,28 @@ func EnvUI(ctx context.Context, app di.Container) error {
 
 	fmt.Println(env)
 
-	return EnvOptionsUI(ctx, s, env, &cfg.Github, platIndex)
+	nav.Push(&envScreen{ctx: ctx, s: s, env: env, ghCfg: &cfg.Github, platIndex: platIndex})
+	return nil
 }
 
-func EnvOptionsUI(ctx context.Context, s *service.Service, env *models.Environment, ghCfg *models.GithubConfig, platIndex int) error {
+type envScreen struct {
+	ctx       context.Context
+	s         *service.Service
+	env       *models.Environment
+	ghCfg     *models.GithubConfig
+	platIndex int
+}
 
-	option, _, err := util.Select(SelectOptionMsg, envOptions.WithBackOption())
-	if err != nil {
-		return fmt.Errorf(PromptFailedMsg, err)
+func (e *envScreen) Title() string {
+	return fmt.Sprintf("Env: %s/%s", e.s.GetConfig().GetPlatform(e.platIndex).Name, e.env.Name)
+}
+
+func (e *envScreen) Run(nav *util.Navigator) error {
+	ctx, s, env, ghCfg, platIndex := e.ctx, e.s, e.env, e.ghCfg, e.platIndex
+
+	option, err := nav.Menu(SelectOptionMsg, envOptions)
+	if err != nil || option < 0 {
+		return err
 	}
 
 	switch option {
@@ -67,20 +83,15 @@ func EnvOptionsUI(ctx context.Context, s *service.Service, env *models.Environme
 		if len(env.Overrides) > 0 {
 
 			overrides := env.Overrides.ToArray()
-			searcherO := func(input string, index int) bool {
-				s := overrides[index]
-				name := strings.Replace(strings.ToLower(s.Name), " ", "", -1)
-				input = strings.Replace(strings.ToLower(input), " ", "", -1)
-				return strings.Contains(name, input)
-			}
 
 			prompt := util.MultiSelect{
-				Label:     "Overrides:",
-				Items:     overrides,
-				Templates: templates,
-				Size:      8,
-				Searcher:  searcherO,
-				HideHelp:  false,
+				Label:       "Overrides:",
+				Items:       overrides,
+				Templates:   templates,
+				Size:        8,
+				Scorer:      serviceMatcher(overrides).Score,
+				HideHelp:    false,
+				HideCounter: true,
 			}
 			_, err = prompt.Run()
 			if err != nil {
@@ -91,20 +102,15 @@ func EnvOptionsUI(ctx context.Context, s *service.Service, env *models.Environme
 		if len(env.Services) > 0 {
 
 			services := env.Services.ToArray()
-			searcher := func(input string, index int) bool {
-				s := services[index]
-				name := strings.Replace(strings.ToLower(s.Name), " ", "", -1)
-				input = strings.Replace(strings.ToLower(input), " ", "", -1)
-				return strings.Contains(name, input)
-			}
 
 			prompt := util.MultiSelect{
-				Label:     "Services:",
-				Items:     services,
-				Templates: templates,
-				Size:      8,
-				Searcher:  searcher,
-				HideHelp:  false,
+				Label:       "Services:",
+				Items:       services,
+				Templates:   templates,
+				Size:        8,
+				Scorer:      serviceMatcher(services).Score,
+				HideHelp:    false,
+				HideCounter: true,
 			}
 			_, err = prompt.Run()
 			if err != nil {
@@ -121,99 +127,46 @@ func EnvOptionsUI(ctx context.Context, s *service.Service, env *models.Environme
 
 		fmt.Printf("Current Version: %s", env.HelmChartVersion)
 
-		releases, err := s.GetHelmVersions(ctx, env, platIndex)
+		releases, err := s.GetHelmVersions(ctx, platIndex)
 		if err != nil {
 			return fmt.Errorf("getting service versions %w", err)
 		}
 
-		versions := releases.Versions()
-		_, fVersion, err := util.Select("Select version", util.Options(versions))
+		release, err := selectRelease("Select version", releases, env.GetHCVersion(), nil)
 		if err != nil {
-			return fmt.Errorf("Prompt failed %v\n", err)
+			return fmt.Errorf(PromptFailedMsg, err)
+		}
+		fVersion := release.Version
+		if fVersion == env.GetHCVersion() {
+			fmt.Printf("%s is already deployed in %s\n", fVersion, env.Name)
+			break
 		}
 
 		githubDetails := github.WithBumpHC(ghCfg, fVersion)
-		err = BumpHelmUI(ctx, s, env, githubDetails, fVersion)
-		if err != nil {
-			fmt.Println(err)
-		}
+		return BumpHelmUI(ctx, s, env, githubDetails, fVersion)
 
 	case 2:
 
 		//
 		fmt.Println("In development")
-	case 3:
-		return nil
 	}
 
-	return EnvOptionsUI(ctx, s, env, ghCfg, platIndex)
+	return nil
 }
 
 func BumpHelmUI(ctx context.Context, s *service.Service, env *models.Environment, gd *github.Commit, version string) error {
-	fmt.Printf("Github Details \n%s", gd)
-
-	options := util.Options{
-		"Change Author Name",
-		"Change Author Email",
-		"Change Commit Message",
-		"Change Branch",
-		"Continue",
-	}.WithBackOption()
-
-	if !env.DirectCommit {
-		fmt.Print(gd.PullRequestInfo())
-		options = append(options, []string{"Change pull request title", "Change pull request description"}...)
-	}
-
-	githubC, _, err := util.Select(SelectOptionMsg, options)
+	submitted, err := CommitForm(gd, s.GetConfig().Github.MainBranch, !env.DirectCommit).Run()
 	if err != nil {
 		return fmt.Errorf(PromptFailedMsg, err)
 	}
-
-	switch githubC {
-	case 0:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Author Name", gd.AuthorName)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
-
-	case 1:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Author Email", gd.AuthorEmail)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
-	case 2:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Commit Message", gd.Message)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
-	case 3:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Branch", gd.Branch)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
-	case 4:
-
-		err = s.UpdateHelmVersion(ctx, env, gd, version)
-		if err != nil {
-			return fmt.Errorf("loading environment services %w", err)
-		}
-		fmt.Printf("Env: %s Helm updated to version %s", env.Name, version)
-		return nil
-	case 5:
+	if !submitted {
 		return nil
-	case 6:
-		gd.PullRequestTitle, err = util.PromptWithDefault("Enter Pull request title", gd.PullRequestTitle)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
-
-	case 7:
-		gd.PullRequestDescription, err = util.PromptWithDefault("Enter Pull request description", gd.PullRequestDescription)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
+	}
 
+	err = s.UpdateHelmVersion(ctx, env, gd, version)
+	if err != nil {
+		return fmt.Errorf("loading environment services %w", err)
 	}
-	return BumpHelmUI(ctx, s, env, gd, version)
+	fmt.Printf("Env: %s Helm updated to version %s", env.Name, version)
+	return nil
 }
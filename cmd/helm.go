@@ -13,28 +13,43 @@ import (
 	"sync"
 )
 
+// _servicesPrompt is answered by --services without a terminal
+const _servicesPrompt = "Select Services to update"
+
 var helmOptions = util.Options{
 	"Info",
 	"Update Service(s) Versions",
 	"Compare Versions",
 }
 
-func HelmUI(ctx context.Context, app di.Container) error {
+func HelmUI(ctx context.Context, app di.Container, nav *util.Navigator) error {
 
-	s := app.Get("service").(*service.Service)
+	s, err := getService(app)
+	if err != nil {
+		return err
+	}
 	cfg := s.GetConfig()
 	platIndex, _, err := util.Select("Select platform", cfg.ListPlatform())
 	if err != nil {
 		return fmt.Errorf(PromptFailedMsg, err)
 	}
 
-	platConfig := cfg.GetPlatform(platIndex)
+	nav.Push(&helmScreen{ctx: ctx, s: s, platCfg: cfg.GetPlatform(platIndex)})
+	return nil
+}
 
-	return HelmOptionsUI(ctx, s, platConfig)
+type helmScreen struct {
+	ctx     context.Context
+	s       *service.Service
+	platCfg *models.PlatformConfig
+}
 
+func (h *helmScreen) Title() string {
+	return "Helm: " + h.platCfg.Name
 }
 
-func HelmOptionsUI(ctx context.Context, s *service.Service, platCfg *models.PlatformConfig) error {
+func (h *helmScreen) Run(nav *util.Navigator) error {
+	ctx, s, platCfg := h.ctx, h.s, h.platCfg
 
 	latest, err := s.GetLatest(ctx, platCfg.HelmChartRepo)
 	if err != nil {
@@ -43,9 +58,9 @@ func HelmOptionsUI(ctx context.Context, s *service.Service, platCfg *models.Plat
 
 	fmt.Printf("Latest Release: \n%s", latest)
 
-	option, _, err := util.Select(SelectOptionMsg, helmOptions.WithBackOption())
-	if err != nil {
-		return fmt.Errorf(PromptFailedMsg, err)
+	option, err := nav.Menu(SelectOptionMsg, helmOptions)
+	if err != nil || option < 0 {
+		return err
 	}
 
 	switch option {
@@ -67,22 +82,29 @@ func HelmOptionsUI(ctx context.Context, s *service.Service, platCfg *models.Plat
 				`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }} ` +
 				`{{ if .Search }}{{ " (" | faint }}{{ .SearchKey | faint }} {{ "to search)" | faint }} {{ end }}` +
 				`{{ " (Press enter to quit)" | faint }}`),
+			Details: `
+--------- {{ .Item.Name }} ----------
+{{ if .Loading }}{{ "Loading latest release..." | faint }}
+{{- else if .Err }}{{ .Err | red }}
+{{- else }}Latest release: {{ .Preview.Version | green }} {{ .Preview.Date.Format "2006-01-02" | faint }}
+{{- if ne .Preview.Version .Item.Version }} {{ "(update available)" | yellow }}{{ end }}
+{{ .Preview.Summary 5 }}{{ end }}`,
 		}
 
-		searcher := func(input string, index int) bool {
-			s := services[index]
-			name := strings.Replace(strings.ToLower(s.Name), " ", "", -1)
-			input = strings.Replace(strings.ToLower(input), " ", "", -1)
-			return strings.Contains(name, input)
+		// the latest release of the highlighted service is loaded in the background
+		preview := func(ctx context.Context, index int) (interface{}, error) {
+			return s.GetServiceLatest(ctx, services[index].Name)
 		}
 
 		prompt := util.MultiSelect{
-			Label:     "Services:",
-			Items:     services,
-			Templates: templates,
-			Size:      8,
-			Searcher:  searcher,
-			HideHelp:  false,
+			Label:       "Services:",
+			Items:       services,
+			Templates:   templates,
+			Size:        8,
+			Scorer:      serviceMatcher(services).Score,
+			Preview:     preview,
+			HideHelp:    false,
+			HideCounter: true,
 		}
 
 		_, err = prompt.Run()
@@ -103,44 +125,26 @@ func HelmOptionsUI(ctx context.Context, s *service.Service, platCfg *models.Plat
 			return fmt.Errorf("getting platform versions %w", err)
 		}
 
-		versions := releases.Versions()
-		fi, fVersion, err := util.SelectWithSearch("Select first version", util.Options(versions), func(input string, index int) bool {
-			s := versions[index]
-			name := strings.Replace(strings.ToLower(s), " ", "", -1)
-			input = strings.Replace(strings.ToLower(input), " ", "", -1)
-			return strings.Contains(name, input)
-		})
+		first, err := selectRelease("Select first version", releases, "", nil)
 		if err != nil {
 			return fmt.Errorf(PromptFailedMsg, err)
 		}
 
-		versions.Remove(fi)
-		_, lVersion, err := util.SelectWithSearch("Select last version", util.Options(versions), func(input string, index int) bool {
-			s := versions[index]
-			name := strings.Replace(strings.ToLower(s), " ", "", -1)
-			input = strings.Replace(strings.ToLower(input), " ", "", -1)
-			return strings.Contains(name, input)
-		})
+		last, err := selectRelease("Select last version", releases, "", first)
 		if err != nil {
 			return fmt.Errorf(PromptFailedMsg, err)
 		}
 
-		diff, err := s.ComparePlatReleasesByVersion(ctx, platCfg, releases, fVersion, lVersion)
+		diff, err := s.ComparePlatReleasesByVersion(ctx, platCfg, releases, first.Version, last.Version)
 		if err != nil {
 			return fmt.Errorf("comparing versions %w", err)
 		}
 		fmt.Println(diff)
 
-		err = VersionsUI(ctx, s, diff)
-		if err != nil {
-			return err
-		}
-
-	case 3:
-		return nil
+		nav.Push(&versionsScreen{ctx: ctx, s: s, diff: diff})
 	}
 
-	return HelmOptionsUI(ctx, s, platCfg)
+	return nil
 }
 
 func BumpServicesUI(ctx context.Context, s *service.Service, platCfg *models.PlatformConfig) error {
@@ -151,7 +155,7 @@ func BumpServicesUI(ctx context.Context, s *service.Service, platCfg *models.Pla
 	}
 
 	services := make([]*models.ServiceUpdated, 0, len(plat.Services))
-	for _, ser := range plat.Services {
+	for _, ser := range plat.Services.ToArray() {
 		services = append(services, &models.ServiceUpdated{Service: ser})
 	}
 
@@ -162,23 +166,29 @@ func BumpServicesUI(ctx context.Context, s *service.Service, platCfg *models.Pla
 		Help: fmt.Sprintf(`{{ "Use the arrow keys to navigate:" | faint }} {{ .NextKey | faint }} ` +
 			`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }} ` +
 			`{{ if .Search }}{{ " (" | faint }}{{ .SearchKey | faint }} {{ "to search)" | faint }} {{ end }}` +
-			`{{ " (" | faint }}{{ .ToggleKey | faint }} {{ "to select)" | faint }}` +
+			`{{ " (" | faint }}{{ .ToggleKey | faint }} {{ "to select," | faint }} ` +
+			`{{ .SelectAllKey | faint }}{{ "/" | faint }}{{ .SelectNoneKey | faint }}{{ "/" | faint }}{{ .InvertKey | faint }} ` +
+			`{{ "all/none/invert shown," | faint }} {{ .SelectedOnlyKey | faint }} {{ "selected only)" | faint }}` +
 			`{{ " (Press enter to quit and save)" | faint }}`),
 	}
 
-	searcher := func(input string, index int) bool {
-		s := services[index]
-		name := strings.Replace(strings.ToLower(s.Service.Name), " ", "", -1)
-		input = strings.Replace(strings.ToLower(input), " ", "", -1)
-		return strings.Contains(name, input)
+	names := make(util.Matcher, 0, len(services))
+	for _, ser := range services {
+		names = append(names, ser.Service.Name)
+	}
+
+	preselected, err := selectServicesByName(services, selectServices)
+	if err != nil {
+		return err
 	}
 
 	prompt := util.MultiSelect{
-		Label:     "Select Services to update",
+		Label:     _servicesPrompt,
 		Items:     services,
+		Selected:  preselected,
 		Templates: templates,
 		Size:      8,
-		Searcher:  searcher,
+		Scorer:    names.Score,
 		HideHelp:  false,
 	}
 
@@ -189,26 +199,22 @@ func BumpServicesUI(ctx context.Context, s *service.Service, platCfg *models.Pla
 
 	selectedServices := make([]*models.ServiceUpdated, 0, len(selected))
 
-	var wg sync.WaitGroup
-
-	rowRes := make(map[int][]string, len(selected))
-
-	for index, option := range selected {
+	for _, option := range selected {
 		selectedServices = append(selectedServices, services[option])
-		wg.Add(1)
-		go func(index int, service *models.ServiceUpdated, w *sync.WaitGroup) {
+	}
 
-			releases, _ := s.GetAvailableServiceReleases(ctx, service.Service)
-			rowRes[index] = releases.Versions()
-			w.Done()
-		}(index, services[option], &wg)
+	toUpdate := make([]*models.Service, 0, len(selectedServices))
+	for _, selectedService := range selectedServices {
+		toUpdate = append(toUpdate, selectedService.Service)
+	}
+	available, errs := s.GetAvailableServicesReleases(ctx, toUpdate)
+	if err := errs.Err(); err != nil {
+		fmt.Printf("could not obtain the versions of some services, type them instead: %s\n", err)
 	}
-	fmt.Println("....Obtaining available versions....")
-	wg.Wait()
 
 	for index := range selectedServices {
-		if versions, ok := rowRes[index]; ok && len(versions) > 0 {
-			_, selectedServices[index].NewVersion, err = util.SelectWithAdd(fmt.Sprintf("%s current (%s)", selectedServices[index].Service.Name, selectedServices[index].Service.Version), versions)
+		if releases := available[index]; len(releases) > 0 {
+			selectedServices[index].NewVersion, err = selectServiceVersion(ctx, s, selectedServices[index].Service, releases)
 		} else {
 			selectedServices[index].NewVersion, err = util.PromptWithDefault(selectedServices[index].Service.Name, selectedServices[index].Service.Version)
 		}
@@ -223,75 +229,129 @@ func BumpServicesUI(ctx context.Context, s *service.Service, platCfg *models.Pla
 
 }
 
-func GithubUI(ctx context.Context, s *service.Service, gd *github.Commit, platCfg *models.PlatformConfig, services []*models.ServiceUpdated) error {
-	fmt.Printf("Github Details \n%s", gd)
+// selectServiceVersion selects the new version of a service among its newer releases, the highlighted release
+// previews its changes since the current version and its full changelog can be shown before using it
+func selectServiceVersion(ctx context.Context, s *service.Service, ser *models.Service, releases models.Releases) (string, error) {
+	var mu sync.Mutex
+	changelogs := make(map[*models.Release]*models.Changelog, len(releases))
+	changelog := func(ctx context.Context, release *models.Release) (*models.Changelog, error) {
+		mu.Lock()
+		cached, ok := changelogs[release]
+		mu.Unlock()
+		if ok {
+			return cached, nil
+		}
 
-	options := util.Options{
-		"Change Author Name",
-		"Change Author Email",
-		"Change Commit Message",
-		"Change Branch",
-		"Continue",
-	}.WithBackOption()
-
-	if !platCfg.DirectCommit {
-		fmt.Print(gd.PullRequestInfo())
-		options = append(options, []string{"Change pull request title", "Change pull request description"}...)
+		loaded, err := s.GetUpdateChangelog(ctx, ser, release)
+		if err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		changelogs[release] = loaded
+		mu.Unlock()
+		return loaded, nil
 	}
 
-	githubC, _, err := util.Select(SelectOptionMsg, options)
-	if err != nil {
-		return fmt.Errorf(PromptFailedMsg, err)
+	labels := releases.Labels(ser.Version)
+	for i, release := range releases {
+		labels[i] = fmt.Sprintf("%s %s", labels[i], release.Date.Format("2006-01-02"))
 	}
 
-	switch githubC {
-	case 0:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Author Name", gd.AuthorName)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
+	preview := &util.Preview{
+		Load: func(ctx context.Context, index int) (interface{}, error) {
+			return changelog(ctx, releases[index])
+		},
+		// the add option has no changes to preview
+		Details: fmt.Sprintf(`{{ with .Item.Item }}
+--------- Changes {{ %q | faint }} → {{ . | faint }} ----------
+{{ if $.Loading }}{{ "Loading changes..." | faint }}
+{{- else if $.Err }}{{ $.Err | red }}
+{{- else if $.Preview }}{{ $.Preview.Summary }}{{ end }}{{ end }}`, ser.Version),
+	}
 
-	case 1:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Author Email", gd.AuthorEmail)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
-	case 2:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Commit Message", gd.Message)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
+	label := fmt.Sprintf("%s current (%s)", ser.Name, ser.Version)
+	for {
+		index, version, err := util.SelectWithAdd(label, util.Options(releases.Versions()), util.Options(labels), preview)
+		if err != nil || index == promptui.SelectedAdd {
+			return version, err
 		}
-	case 3:
-		gd.AuthorName, err = util.PromptWithDefault("Enter Branch", gd.Branch)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
+
+		for {
+			options := util.Options{"Use " + version, "Show changelog", "Select another version"}
+			option, _, err := util.Select(fmt.Sprintf("%s %s -> %s", ser.Name, ser.Version, version), options)
+			if err != nil {
+				return "", err
+			}
+			if option == 0 {
+				return version, nil
+			}
+			if option == 2 {
+				break
+			}
+
+			cl, err := changelog(ctx, releases[index])
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			fmt.Print(cl)
 		}
-	case 4:
+	}
+}
 
-		err = s.UpdateServicesVersions(ctx, platCfg, gd, services)
-		if err != nil {
-			return fmt.Errorf("error updating services %w", err)
+// selectServicesByName returns the indexes of the services named, e.g. with --services
+func selectServicesByName(services []*models.ServiceUpdated, names []string) ([]int, error) {
+	indexes := make([]int, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		index := -1
+		for i, ser := range services {
+			if strings.EqualFold(ser.Service.Name, name) {
+				index = i
+				break
+			}
 		}
-		return nil
-	case 5:
-		return nil
-	case 6:
-		gd.PullRequestTitle, err = util.PromptWithDefault("Enter Pull request title", gd.PullRequestTitle)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
+		if index < 0 {
+			unknown = append(unknown, name)
+			continue
 		}
+		indexes = append(indexes, index)
+	}
 
-	case 7:
-		gd.PullRequestDescription, err = util.PromptWithDefault("Enter Pull request description", gd.PullRequestDescription)
-		if err != nil {
-			return fmt.Errorf(PromptFailedMsg, err)
-		}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("services not in the helm chart: %s", strings.Join(unknown, ", "))
+	}
+	return indexes, nil
+}
+
+func GithubUI(ctx context.Context, s *service.Service, gd *github.Commit, platCfg *models.PlatformConfig, services []*models.ServiceUpdated) error {
+	submitted, err := CommitForm(gd, s.GetConfig().Github.MainBranch, !platCfg.DirectCommit).Run()
+	if err != nil {
+		return fmt.Errorf(PromptFailedMsg, err)
+	}
+	if !submitted {
+		return nil
+	}
 
+	err = s.UpdateServicesVersions(ctx, platCfg, gd, services)
+	if err != nil {
+		return fmt.Errorf("error updating services %w", err)
 	}
-	return GithubUI(ctx, s, gd, platCfg, services)
+	return nil
+}
+
+type versionsScreen struct {
+	ctx  context.Context
+	s    *service.Service
+	diff *models.Comparer
+}
+
+func (v *versionsScreen) Title() string {
+	return fmt.Sprintf("%s -> %s", v.diff.InitialVersion, v.diff.FinalVersion)
 }
 
-func VersionsUI(ctx context.Context, s *service.Service, diff *models.Comparer) error {
+func (v *versionsScreen) Run(nav *util.Navigator) error {
+	ctx, s, diff := v.ctx, v.s, v.diff
 
 	options := util.Options{
 		"Show Changelogs",
@@ -299,9 +359,9 @@ func VersionsUI(ctx context.Context, s *service.Service, diff *models.Comparer)
 		"Create Release Ticket (In development)",
 	}
 
-	option, _, err := util.Select(SelectOptionMsg, options.WithBackOption())
-	if err != nil {
-		return fmt.Errorf(PromptFailedMsg, err)
+	option, err := nav.Menu(SelectOptionMsg, options)
+	if err != nil || option < 0 {
+		return err
 	}
 
 	switch option {
@@ -311,24 +371,39 @@ func VersionsUI(ctx context.Context, s *service.Service, diff *models.Comparer)
 			return err
 		}
 
-		for key, changelog := range changelogs {
-			fmt.Printf("\nService Repo: %s\n", key)
-			fmt.Println(changelog)
-		}
+		fmt.Println(changelogs)
 	case 1:
-		err := s.ExportRelease(ctx, diff)
+		_, format, err := util.Select("Select format", s.ExportFormats())
+		if err != nil {
+			return fmt.Errorf(PromptFailedMsg, err)
+		}
+
+		archives := s.ArchiveOptions()
+		archiveOptions := make(util.Options, 0, len(archives))
+		for _, archive := range archives {
+			if archive == "" {
+				archiveOptions = append(archiveOptions, "Folder")
+				continue
+			}
+			archiveOptions = append(archiveOptions, fmt.Sprintf("%s archive with manifest", archive))
+		}
+
+		archiveIndex, _, err := util.Select("Export to", archiveOptions)
+		if err != nil {
+			return fmt.Errorf(PromptFailedMsg, err)
+		}
+
+		dir, err := s.ExportRelease(ctx, diff, service.ExportOptions{Format: format, Archive: archives[archiveIndex]})
 		if err != nil {
 			fmt.Println(promptui.IconBad + " Release not exported")
 			return err
 		}
-		fmt.Println(promptui.IconGood + " Release exported")
+		fmt.Printf("%s Release exported to %s\n", promptui.IconGood, dir)
 
 	case 2:
 		//todo
 		fmt.Println("In development")
-	case 3:
-		return nil
 	}
 
-	return VersionsUI(ctx, s, diff)
+	return nil
 }
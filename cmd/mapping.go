@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var mappingCmd = &cobra.Command{
+	Use:   "mapping",
+	Short: "Tools for the service to repo mapping",
+}
+
+var mappingTestCmd = &cobra.Command{
+	Use:   "test <hlmName>",
+	Short: "Explain which service rule resolves a chart service name",
+	Long:  `Checks the configured service rules in order against a chart service name and shows the rule that resolved it`,
+	Args:  cobra.ExactArgs(1),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app := internal.CreateApp(context.Background())
+		if app == nil {
+			return errors.New("error generation application")
+		}
+
+		config, err := (*app).SafeGet("config")
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(MappingExplain(config.(*models.Config).Services, args[0]))
+		return nil
+	},
+}
+
+// MappingExplain lists the rules checked for a chart service name and how it was resolved
+func MappingExplain(rules models.ServiceRules, serviceName string) string {
+	resolved := rules.Resolve(serviceName)
+
+	str := fmt.Sprintf("Service: %s\n", serviceName)
+	for i, rule := range rules {
+		if i == resolved.RuleIndex {
+			str += fmt.Sprintf(" %d. %s matched\n", i+1, rule)
+			break
+		}
+		str += fmt.Sprintf(" %d. %s no match\n", i+1, rule)
+	}
+
+	if resolved.Rule == nil {
+		str += fmt.Sprintf("No rule matched, using the default repo %s\n", resolved.Repo)
+		return str
+	}
+	if resolved.Ignore {
+		str += "Ignored\n"
+		return str
+	}
+
+	str += fmt.Sprintf("Repo: %s\n", resolved.Repo)
+	if resolved.Mapping.MultiTag {
+		str += fmt.Sprintf("Multi tag: %s\n", resolved.Tag("<version>"))
+	}
+	return str
+}
+
+func init() {
+	mappingCmd.AddCommand(mappingTestCmd)
+	rootCmd.AddCommand(mappingCmd)
+}
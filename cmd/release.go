@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/service"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Tools for exported releases",
+}
+
+var releaseVerifyCmd = &cobra.Command{
+	Use:   "verify <archive>",
+	Short: "Verify the integrity of an exported release archive",
+	Long:  `Checks every file of a tar.gz or zip release archive against the checksums in its manifest.json`,
+	Args:  cobra.ExactArgs(1),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest, err := service.VerifyArchive(args[0])
+		if manifest != nil {
+			fmt.Print(manifest)
+		}
+		if err != nil {
+			fmt.Println(promptui.IconBad + " Archive not verified")
+			return err
+		}
+		fmt.Println(promptui.IconGood + " Archive verified")
+		return nil
+	},
+}
+
+func init() {
+	releaseCmd.AddCommand(releaseVerifyCmd)
+	rootCmd.AddCommand(releaseCmd)
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/adam-putland/divido-cli/internal"
+	"github.com/adam-putland/divido-cli/internal/service"
 	"github.com/adam-putland/divido-cli/internal/util"
 	"github.com/sarulabs/di"
 	"os"
@@ -18,12 +19,17 @@ import (
 )
 
 var (
-	cfgFile string
-	options = []string{
+	cfgFile        string
+	profile        string
+	configLayers   *internal.ConfigLayers
+	answersFile    string
+	recordFile     string
+	selectServices []string
+	noCache        bool
+	options        = util.Options{
 		"Services query",
 		"Helm query",
 		"Environments query",
-		"Exit",
 	}
 )
 
@@ -35,6 +41,8 @@ var rootCmd = &cobra.Command{
 	Use:   "divido-cli",
 	Short: "A cli for Divido devs",
 	Long:  `This cli provides tools for deploying services, updating helm charts and updating environments`,
+	// errors are not usage errors once the flags are parsed
+	SilenceUsage: true,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
@@ -46,35 +54,54 @@ var rootCmd = &cobra.Command{
 	},
 }
 
-func Run(ctx context.Context, app di.Container) error {
-	index, _, err := util.Select(SelectOptionMsg, options)
+// getService builds the service of the app, failing when the config cannot be loaded
+func getService(app di.Container) (*service.Service, error) {
+	s, err := app.SafeGet("service")
 	if err != nil {
-		return fmt.Errorf("select failed %w", err)
+		return nil, err
+	}
+	return s.(*service.Service), nil
+}
+
+func Run(ctx context.Context, app di.Container) error {
+	return util.NewNavigator(&homeScreen{ctx: ctx, app: app}).Run()
+}
+
+type homeScreen struct {
+	ctx context.Context
+	app di.Container
+}
+
+func (h *homeScreen) Title() string {
+	return "Home"
+}
+
+func (h *homeScreen) Run(nav *util.Navigator) error {
+	index, err := nav.Menu(SelectOptionMsg, options)
+	if err != nil || index < 0 {
+		return err
 	}
 
-	var errUI error
 	switch index {
 	case 0:
-		errUI = ServiceUI(ctx, app)
+		return ServiceUI(h.ctx, h.app, nav)
 	case 1:
-		errUI = HelmUI(ctx, app)
+		return HelmUI(h.ctx, h.app, nav)
 	case 2:
-		errUI = EnvUI(ctx, app)
-	case 3:
-		return nil
+		return EnvUI(h.ctx, h.app, nav)
 	}
-
-	if errUI != nil {
-		fmt.Println(errUI)
-	}
-	return Run(ctx, app)
-
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	if recordFile != "" {
+		if recordErr := util.DefaultPrompter().SaveRecording(recordFile); recordErr != nil {
+			fmt.Fprintln(os.Stderr, "Saving answers:", recordErr)
+		}
+	}
 	if err != nil {
 		os.Exit(1)
 	}
@@ -87,35 +114,43 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/config.json)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file merged after the system, $HOME and ./config.json files")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "profile merging config.<profile>.json after each config.json (default is $DIVIDO_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&answersFile, "answers", "", "answers file replayed before prompting, e.g. recorded with --record")
+	rootCmd.PersistentFlags().StringVar(&recordFile, "record", "", "record the answers of the session to a file replayable with --answers")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "load every helm chart from GitHub instead of the cached snapshots")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
+	rootCmd.Flags().StringSliceVar(&selectServices, "services", nil, "services preselected when choosing the services to update, e.g. --services api,portal")
+	util.DefaultPrompter().Flag(_servicesPrompt, "--services")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
-// initConfig reads in config file and ENV variables if set.
+// initConfig merges the config layers and DIVIDO_ env vars into viper.
 func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		// Search config in home directory with name "config" (without extension).
-		viper.AddConfigPath(".")
-		viper.AddConfigPath(home)
-
-		viper.SetConfigType("json")
-		viper.SetConfigName("config")
+	layers, err := internal.LoadConfigLayers(viper.GetViper(), internal.DefaultConfigLayerOptions(cfgFile, profile))
+	cobra.CheckErr(err)
+	configLayers = layers
+
+	for _, layer := range layers.Layers {
+		if layer.Path != "" {
+			fmt.Fprintln(os.Stderr, "Using config file:", layer.Path)
+		}
+	}
+	for _, warning := range layers.Warnings {
+		fmt.Fprintln(os.Stderr, "Config:", warning)
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	if noCache {
+		service.DisableSnapshots()
+	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	prompter := util.DefaultPrompter()
+	if answersFile != "" {
+		cobra.CheckErr(prompter.Replay(answersFile))
+	}
+	if recordFile != "" {
+		prompter.Record()
 	}
 }
@@ -3,97 +3,129 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
 	"github.com/adam-putland/divido-cli/internal/service"
 	"github.com/adam-putland/divido-cli/internal/util"
 	"github.com/sarulabs/di"
-	"strings"
 )
 
 var serviceOptions = util.Options{
 	"Versions",
 	"Generate Changelog",
+	"Deployments",
 }
 
-func ServiceUI(ctx context.Context, app di.Container) error {
-	s := app.Get("service").(*service.Service)
+func ServiceUI(ctx context.Context, app di.Container, nav *util.Navigator) error {
+	s, err := getService(app)
+	if err != nil {
+		return err
+	}
 	serviceName, err := util.Prompt("Enter service")
 	if err != nil {
 		return fmt.Errorf(PromptFailedMsg, err)
 	}
 
-	serv, err := s.GetLatest(ctx, serviceName)
+	serv, err := s.GetServiceLatest(ctx, serviceName)
 	if err != nil {
 		return fmt.Errorf("getting service %w", err)
 	}
 	fmt.Println(serv)
 
-	return ServiceOptionsUI(ctx, s, serviceName)
+	nav.Push(&serviceScreen{ctx: ctx, s: s, serviceName: serviceName})
+	return nil
 }
 
-func ServiceOptionsUI(ctx context.Context, s *service.Service, serviceName string) error {
-	option, _, err := util.Select(SelectOptionMsg, serviceOptions.WithBackOption())
-	if err != nil {
-		return fmt.Errorf(PromptFailedMsg, err)
+type serviceScreen struct {
+	ctx         context.Context
+	s           *service.Service
+	serviceName string
+}
+
+func (sc *serviceScreen) Title() string {
+	return "Service: " + sc.serviceName
+}
+
+func (sc *serviceScreen) Run(nav *util.Navigator) error {
+	ctx, s, serviceName := sc.ctx, sc.s, sc.serviceName
+
+	option, err := nav.Menu(SelectOptionMsg, serviceOptions)
+	if err != nil || option < 0 {
+		return err
 	}
 
 	switch option {
 	case 0:
-		releases, err := s.GetRepoReleases(ctx, serviceName)
+		releases, err := s.GetServiceReleases(ctx, serviceName)
 		if err != nil {
 			return fmt.Errorf("getting service versions %w", err)
 		}
 
-		versions := releases.Versions()
-		_, _, err = util.SelectWithSearch("Versions", util.Options(releases.Versions()), func(input string, index int) bool {
-			s := versions[index]
-			name := strings.Replace(strings.ToLower(s), " ", "", -1)
-			input = strings.Replace(strings.ToLower(input), " ", "", -1)
-			return strings.Contains(name, input)
-		})
+		_, err = selectRelease("Versions", releases, "", nil)
 		if err != nil {
 			return fmt.Errorf(PromptFailedMsg, err)
 		}
 
 	case 1:
 
-		releases, err := s.GetRepoReleases(ctx, serviceName)
+		releases, err := s.GetServiceReleases(ctx, serviceName)
 		if err != nil {
 			return fmt.Errorf("getting service versions %w", err)
 		}
 
-		versions := releases.Versions()
-
-		fi, fVersion, err := util.SelectWithSearch("Select first version", util.Options(versions), func(input string, index int) bool {
-			s := versions[index]
-			name := strings.Replace(strings.ToLower(s), " ", "", -1)
-			input = strings.Replace(strings.ToLower(input), " ", "", -1)
-			return strings.Contains(name, input)
-		})
+		first, err := selectRelease("Select first version", releases, "", nil)
 		if err != nil {
 			return fmt.Errorf(PromptFailedMsg, err)
 		}
 
-		versions.Remove(fi)
-
-		_, sVersion, err := util.SelectWithSearch("Select last version", util.Options(versions), func(input string, index int) bool {
-			s := versions[index]
-			name := strings.Replace(strings.ToLower(s), " ", "", -1)
-			input = strings.Replace(strings.ToLower(input), " ", "", -1)
-			return strings.Contains(name, input)
-		})
+		last, err := selectRelease("Select last version", releases, "", first)
 		if err != nil {
 			return fmt.Errorf(PromptFailedMsg, err)
 		}
 
-		changelog, err := s.GetChangelog(ctx, serviceName, releases.GetReleaseByVersion(fVersion), releases.GetReleaseByVersion(sVersion))
+		changelog, err := s.GetChangelog(ctx, serviceName, first, last)
 		if err != nil {
 			return fmt.Errorf("getting changelog %w", err)
 		}
 		fmt.Print(changelog)
 
 	case 2:
-		return nil
+
+		deployments, err := s.GetServiceDeployments(ctx, serviceName)
+		if err != nil {
+			return fmt.Errorf("getting service deployments %w", err)
+		}
+		fmt.Print(deployments)
+	}
+
+	return nil
+}
+
+// serviceMatcher fuzzy matches the search of a prompt against the service names
+func serviceMatcher(services []*models.Service) util.Matcher {
+	names := make(util.Matcher, 0, len(services))
+	for _, ser := range services {
+		names = append(names, ser.Name)
+	}
+	return names
+}
+
+// selectRelease selects one of the releases but the excluded one, the deployed version, the latest release and
+// the prereleases are marked
+func selectRelease(label string, releases models.Releases, deployed string, exclude *models.Release) (*models.Release, error) {
+	labels := releases.Labels(deployed)
+
+	shown := make(models.Releases, 0, len(releases))
+	shownLabels := make(util.Options, 0, len(releases))
+	for i, release := range releases {
+		if release != exclude {
+			shown = append(shown, release)
+			shownLabels = append(shownLabels, labels[i])
+		}
 	}
 
-	return ServiceOptionsUI(ctx, s, serviceName)
+	index, _, err := util.SelectWithLabels(label, util.Options(shown.Versions()), shownLabels)
+	if err != nil {
+		return nil, err
+	}
+	return shown[index], nil
 }
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+)
+
+// ConfigSchemaURL is the published JSON Schema of the config file
+const ConfigSchemaURL = "https://raw.githubusercontent.com/adam-putland/divido-cli/main/config.schema.json"
+
+// LoadConfig decodes the config strictly, unknown fields are rejected, and validates it
+func LoadConfig(v *viper.Viper) (*models.Config, error) {
+	var config models.Config
+
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		models.ServiceRulesHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := v.UnmarshalExact(&config, hook); err != nil {
+		return nil, fmt.Errorf("reading config %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SaveConfig validates the config and writes it as JSON to the given path
+func SaveConfig(path string, config *models.Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating config dir %w", err)
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
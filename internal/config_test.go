@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"github.com/adam-putland/divido-cli/internal/models"
+	"github.com/spf13/viper"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "valid", config: `{"$schema": "./config.schema.json", "github": {"org": "dividohq"},
+			"platforms": [{"name": "ing", "hlm": "ing-platform-hlm", "envs": [{"name": "test", "repo": "ing-platform-test-inf"}]}],
+			"services": [{"match": ".*GraphqlApi.*", "repo": "graphql-apis", "multiTag": true}]}`, wantErr: false},
+		{name: "legacy_services", config: `{"services": [{".*GraphqlApi.*": {"repo": "graphql-apis", "multiTag": true}}]}`, wantErr: false},
+		{name: "unknown_field", config: `{"services": [{"match": ".*GraphqlApi.*", "repo": "graphql-apis", "multi-tag": true}]}`, wantErr: true},
+		{name: "unknown_section", config: `{"platform": []}`, wantErr: true},
+		{name: "invalid_regex", config: `{"services": [{"match": "(", "repo": "graphql-apis"}]}`, wantErr: true},
+		{name: "missing_hlm", config: `{"platforms": [{"name": "ing"}]}`, wantErr: true},
+		{name: "negative_concurrency", config: `{"github": {"org": "dividohq", "concurrency": -1}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("json")
+			if err := v.ReadConfig(strings.NewReader(tt.config)); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadConfig(v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveConfig(t *testing.T) {
+
+	config := &models.Config{
+		Schema: ConfigSchemaURL,
+		Github: models.GithubConfig{Org: "dividohq", MainBranch: "master"},
+		Platforms: []models.PlatformConfig{{Name: "ing", HelmChartRepo: "ing-platform-hlm", Envs: []models.EnvironmentConfig{
+			{Name: "test", Repo: "ing-platform-test-inf", DirectCommit: true},
+		}}},
+		Services: models.ServiceRules{{Match: ".*GraphqlApi.*", ServiceMapping: models.ServiceMapping{Repo: "graphql-apis", MultiTag: true}}},
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := SaveConfig(path, config); err != nil {
+		t.Fatal(err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadConfig(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got.Platforms, config.Platforms) || got.Github != config.Github || got.Services[0].ServiceMapping != config.Services[0].ServiceMapping {
+		t.Errorf("LoadConfig() got = %v, want %v", got, config)
+	}
+}
@@ -17,25 +17,25 @@ func CreateApp(ctx context.Context) *di.Container {
 			Name:  "github",
 			Scope: di.App,
 			Build: func(ctn di.Container) (interface{}, error) {
-				return github.NewGithubClient(ctx, viper.GetString("GITHUB_TOKEN")), nil
+				return github.NewGithubClient(ctx, GithubToken()), nil
 			},
 			Close: nil},
 		{
 			Name:  "config",
 			Scope: di.App,
 			Build: func(ctn di.Container) (interface{}, error) {
-				var config models.Config
-				if err := viper.Unmarshal(&config); err != nil {
-					return nil, err
-				}
-				return &config, nil
+				return LoadConfig(viper.GetViper())
 			},
 			Close: nil},
 		{
 			Name:  "service",
 			Scope: di.App,
 			Build: func(ctn di.Container) (interface{}, error) {
-				return service.New(ctn.Get("github").(*github.GithubClient), ctn.Get("config").(*models.Config)), nil
+				config, err := ctn.SafeGet("config")
+				if err != nil {
+					return nil, err
+				}
+				return service.New(ctn.Get("github").(*github.GithubClient), config.(*models.Config)), nil
 			},
 			Close: nil},
 	}...)
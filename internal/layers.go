@@ -0,0 +1,300 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvPrefix prefixes the env vars overriding config values, levels are separated by _envSeparator,
+	// e.g. DIVIDO_GITHUB__MAINBRANCH sets github.mainBranch
+	EnvPrefix = "DIVIDO_"
+
+	_envSeparator = "__"
+
+	_systemConfigDir = "/etc/divido-cli"
+	_configName      = "config"
+	_configExt       = ".json"
+	_envProfile      = EnvPrefix + "PROFILE"
+	_envGithubToken  = "GITHUB_TOKEN"
+)
+
+// ConfigLayerOptions sets where the config layers are read from, empty dirs are skipped
+type ConfigLayerOptions struct {
+	SystemDir string
+	UserDir   string
+	LocalDir  string
+	// ConfigFile is the explicit --config file, merged after the other files
+	ConfigFile string
+	// Profile also merges config.<profile>.json after the config.json of each dir
+	Profile string
+	// Environ are the env vars, as returned by os.Environ
+	Environ []string
+}
+
+// ConfigLayer is a source merged into the config
+type ConfigLayer struct {
+	Name string
+	Path string
+}
+
+func (l ConfigLayer) String() string {
+	if l.Path == "" {
+		return l.Name
+	}
+	return fmt.Sprintf("%s %s", l.Name, l.Path)
+}
+
+// ConfigLayers are the sources merged into the config in order, Sources has the layer setting each key
+type ConfigLayers struct {
+	Layers  []ConfigLayer
+	Sources map[string]ConfigLayer
+	// Warnings lists the env vars of config keys ignored as their value is invalid
+	Warnings []string
+}
+
+// envKey is a config key settable by an env var
+type envKey struct {
+	key  string
+	kind reflect.Kind
+}
+
+// _envKeys are the config keys settable by env vars keyed by env var name
+var _envKeys = envKeys(reflect.TypeOf(models.Config{}), "", make(map[string]envKey))
+
+// DefaultConfigLayerOptions reads the system, user ($HOME) and repo-local (.) dirs, the profile defaults to $DIVIDO_PROFILE
+func DefaultConfigLayerOptions(configFile, profile string) ConfigLayerOptions {
+	home, _ := os.UserHomeDir()
+	if profile == "" {
+		profile = os.Getenv(_envProfile)
+	}
+
+	return ConfigLayerOptions{
+		SystemDir:  _systemConfigDir,
+		UserDir:    home,
+		LocalDir:   ".",
+		ConfigFile: configFile,
+		Profile:    profile,
+		Environ:    os.Environ(),
+	}
+}
+
+// LoadConfigLayers merges in order the system, user and repo-local config files, each followed by its profile file,
+// the explicit config file and the DIVIDO_ env vars of config keys. Later layers override earlier ones, lists are
+// replaced as a whole
+func LoadConfigLayers(v *viper.Viper, opts ConfigLayerOptions) (*ConfigLayers, error) {
+	layers := &ConfigLayers{Sources: make(map[string]ConfigLayer)}
+	seen := make(map[string]bool)
+
+	merge := func(name, path string) error {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if seen[abs] {
+			return nil
+		}
+		seen[abs] = true
+
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		fileViper := viper.New()
+		fileViper.SetConfigFile(path)
+		if err := fileViper.ReadInConfig(); err != nil {
+			return fmt.Errorf("reading %s config %s %w", name, path, err)
+		}
+
+		layer := ConfigLayer{Name: name, Path: path}
+		settings := fileViper.AllSettings()
+		layers.add(layer, "", settings)
+		return v.MergeConfigMap(settings)
+	}
+
+	for _, dir := range []struct{ name, path string }{
+		{name: "system", path: opts.SystemDir},
+		{name: "user", path: opts.UserDir},
+		{name: "local", path: opts.LocalDir},
+	} {
+		if dir.path == "" {
+			continue
+		}
+		if err := merge(dir.name, filepath.Join(dir.path, _configName+_configExt)); err != nil {
+			return nil, err
+		}
+		if opts.Profile == "" {
+			continue
+		}
+		if err := merge(dir.name+" profile", filepath.Join(dir.path, _configName+"."+opts.Profile+_configExt)); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ConfigFile != "" {
+		if _, err := os.Stat(opts.ConfigFile); err != nil {
+			return nil, fmt.Errorf("reading config %w", err)
+		}
+		if err := merge("explicit", opts.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
+	hasEnv := false
+	for _, env := range opts.Environ {
+		// other DIVIDO_ vars, e.g. of the services deployed, are not config keys
+		name, value, ok := strings.Cut(env, "=")
+		known, isKey := _envKeys[name]
+		if !ok || !isKey {
+			continue
+		}
+
+		parsed, err := known.parse(value)
+		if err != nil {
+			layers.Warnings = append(layers.Warnings, fmt.Sprintf("ignoring %s, %s expects a %s value", name, known.key, known.kind))
+			continue
+		}
+		v.Set(known.key, parsed)
+		layers.Sources[known.key] = ConfigLayer{Name: "env", Path: name}
+		hasEnv = true
+	}
+	if hasEnv {
+		layers.Layers = append(layers.Layers, ConfigLayer{Name: "env"})
+	}
+
+	return layers, nil
+}
+
+// envKeys adds the env var of every string, bool, int and string list key of the config struct, lists of objects
+// and maps are only set by config files
+func envKeys(t reflect.Type, prefix string, keys map[string]envKey) map[string]envKey {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		if !field.IsExported() || strings.HasPrefix(name, "$") {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		envName := EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", _envSeparator))
+
+		switch kind := field.Type.Kind(); {
+		case kind == reflect.Struct:
+			envKeys(field.Type, key, keys)
+		case kind == reflect.String || kind == reflect.Bool || kind == reflect.Int:
+			keys[envName] = envKey{key: key, kind: kind}
+		case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+			keys[envName] = envKey{key: key, kind: kind}
+		}
+	}
+	return keys
+}
+
+// parse converts the env var value to the type of the key, lists are comma separated
+func (k envKey) parse(value string) (interface{}, error) {
+	switch k.kind {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int:
+		return strconv.Atoi(value)
+	case reflect.Slice:
+		return strings.Split(value, ","), nil
+	}
+	return value, nil
+}
+
+// GithubToken returns the token from $DIVIDO_GITHUB_TOKEN, falling back to $GITHUB_TOKEN
+func GithubToken() string {
+	if token := os.Getenv(EnvPrefix + _envGithubToken); token != "" {
+		return token
+	}
+	return os.Getenv(_envGithubToken)
+}
+
+// add records the layer as the source of every key it sets, nested maps are flattened as viper keys
+func (l *ConfigLayers) add(layer ConfigLayer, prefix string, settings map[string]interface{}) {
+	if prefix == "" {
+		l.Layers = append(l.Layers, layer)
+	}
+
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			l.add(layer, key, nested)
+			continue
+		}
+		l.Sources[key] = layer
+	}
+}
+
+// Resolved lists every effective config value with the layer it came from
+func (l ConfigLayers) Resolved(v *viper.Viper) string {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		source, ok := l.Sources[key]
+		if !ok {
+			// an empty map, set by the layer of its parent key
+			source = l.parentSource(key)
+		}
+		fmt.Fprintf(&builder, "%s = %s (%s)\n", key, formatValue(v.Get(key)), source)
+	}
+	return builder.String()
+}
+
+// formatValue prints lists as JSON, as they are set as a whole by a single layer
+func formatValue(value interface{}) string {
+	if _, ok := value.([]interface{}); ok {
+		if content, err := json.Marshal(value); err == nil {
+			return string(content)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func (l ConfigLayers) parentSource(key string) ConfigLayer {
+	for {
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			return ConfigLayer{Name: "default"}
+		}
+		key = key[:i]
+		if source, ok := l.Sources[key]; ok {
+			return source
+		}
+	}
+}
+
+func (l ConfigLayers) String() string {
+	if len(l.Layers) == 0 {
+		return "No config layers loaded\n"
+	}
+
+	var builder strings.Builder
+	for i, layer := range l.Layers {
+		fmt.Fprintf(&builder, " %d. %s\n", i+1, layer)
+	}
+	return builder.String()
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigLayers(t *testing.T) {
+
+	systemDir, userDir, localDir := t.TempDir(), t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(systemDir, "config.json"):         `{"github": {"org": "dividohq", "mainBranch": "master"}, "jira": {"url": "https://divido.atlassian.net"}}`,
+		filepath.Join(userDir, "config.json"):           `{"github": {"authorName": "jane"}}`,
+		filepath.Join(userDir, "config.ing-team.json"):  `{"jira": {"projects": ["ING"]}}`,
+		filepath.Join(localDir, "config.json"):          `{"github": {"mainBranch": "main"}}`,
+		filepath.Join(localDir, "config.explicit.json"): `{"github": {"authorEmail": "jane@divido.com"}}`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v := viper.New()
+	layers, err := LoadConfigLayers(v, ConfigLayerOptions{
+		SystemDir:  systemDir,
+		UserDir:    userDir,
+		LocalDir:   localDir,
+		ConfigFile: filepath.Join(localDir, "config.explicit.json"),
+		Profile:    "ing-team",
+		Environ: []string{"DIVIDO_GITHUB__AUTHORNAME=bot", "DIVIDO_PROFILE=ing-team", "DIVIDO_GITHUB_TOKEN=token", "HOME=/root",
+			"DIVIDO_NGINX_CLIENT_MAX_BODY_SIZE=10m", "DIVIDO_JIRA__PROJECTS=ING,DIV", "DIVIDO_GITHUB__CONCURRENCY=abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(layers.Layers) != 6 {
+		t.Errorf("LoadConfigLayers() got = %v layers, want 6", len(layers.Layers))
+	}
+	if len(layers.Warnings) != 1 {
+		t.Errorf("LoadConfigLayers() got = %v warnings, want the invalid concurrency", layers.Warnings)
+	}
+
+	tests := []struct {
+		key        string
+		want       string
+		wantSource string
+	}{
+		{key: "github.org", want: "dividohq", wantSource: "system"},
+		{key: "github.mainbranch", want: "main", wantSource: "local"},
+		{key: "github.authorname", want: "bot", wantSource: "env"},
+		{key: "github.authoremail", want: "jane@divido.com", wantSource: "explicit"},
+		{key: "jira.url", want: "https://divido.atlassian.net", wantSource: "system"},
+		{key: "jira.projects", want: "[ING DIV]", wantSource: "env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := fmt.Sprint(v.Get(tt.key)); got != tt.want {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+			if got := layers.Sources[tt.key].Name; got != tt.wantSource {
+				t.Errorf("Sources got = %v, want %v", got, tt.wantSource)
+			}
+		})
+	}
+
+	if _, err := LoadConfig(v); err != nil {
+		t.Errorf("LoadConfig() error = %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ChangeFeat  = "feat"
+	ChangeFix   = "fix"
+	ChangePerf  = "perf"
+	ChangeChore = "chore"
+	ChangeOther = "other"
+)
+
+var (
+	_conventionalRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	_pullRequestRegex  = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+	_mergeRegex        = regexp.MustCompile(`^Merge pull request #(\d+) from (\S+)`)
+	_releaseNoteRegex  = regexp.MustCompile(`^[*-]\s+(.+?)(?:\s+by\s+@(\S+))?(?:\s+in\s+(\S+/pull/(\d+)))?$`)
+
+	// groups are rendered in this order, anything not listed falls under "Other Changes"
+	_changeGroups = []struct {
+		Type  string
+		Title string
+	}{
+		{ChangeFeat, "Features"},
+		{ChangeFix, "Bug Fixes"},
+		{ChangePerf, "Performance Improvements"},
+		{ChangeChore, "Chores"},
+		{ChangeOther, "Other Changes"},
+	}
+)
+
+// Change is a single entry of a changelog, parsed from a commit message or a release note line
+type Change struct {
+	Type        string
+	Scope       string
+	Description string
+	Breaking    bool
+	PullRequest int
+	Author      string
+	SHA         string
+	Message     string
+}
+
+type ChangeGroup struct {
+	Title   string
+	Changes []*Change
+}
+
+type Changelog struct {
+	Org     string
+	Repo    string
+	From    string
+	To      string
+	Changes []*Change
+	// Notes are remarks about how the changelog was built, e.g. a missing release
+	Notes []string
+	// Removed is set when the service was removed in the release, To is its last deployed version
+	Removed bool
+	// URL is the release of To of a removed service, empty when it has no release
+	URL string
+}
+
+// Changelogs holds the changelog of each service repo, keyed by repo name. Services of multi tag repos have their
+// own changelog keyed by their tag prefix, see ResolvedService.Key
+type Changelogs map[string]*Changelog
+
+// ParseCommit parses a commit message following the conventional commits spec
+// (https://www.conventionalcommits.org). Messages not following the spec are
+// kept as a change of type "other".
+func ParseCommit(message string) *Change {
+	change := &Change{Message: message}
+
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	subject := strings.TrimSpace(lines[0])
+	body := lines[1:]
+
+	// merge commits keep the pull request title in the body
+	if matches := _mergeRegex.FindStringSubmatch(subject); matches != nil {
+		change.PullRequest, _ = strconv.Atoi(matches[1])
+		subject = matches[2]
+		for i, line := range body {
+			if line = strings.TrimSpace(line); line != "" {
+				subject = line
+				body = body[i+1:]
+				break
+			}
+		}
+	}
+
+	if matches := _pullRequestRegex.FindStringSubmatch(subject); matches != nil {
+		change.PullRequest, _ = strconv.Atoi(matches[1])
+		subject = strings.TrimSuffix(subject, matches[0])
+	}
+
+	change.Type = ChangeOther
+	change.Description = subject
+	if matches := _conventionalRegex.FindStringSubmatch(subject); matches != nil {
+		change.Type = strings.ToLower(matches[1])
+		change.Scope = matches[2]
+		change.Breaking = matches[3] == "!"
+		change.Description = matches[4]
+	}
+
+	for _, line := range body {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			change.Breaking = true
+		}
+	}
+
+	return change
+}
+
+// ParseReleaseNotes parses the release notes generated by GitHub, where each
+// change is a line like "* feat: title by @author in https://github.com/org/repo/pull/1"
+func ParseReleaseNotes(body string) []*Change {
+	var changes []*Change
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+
+		// the new contributors section is the last one before the full changelog link
+		if strings.HasPrefix(line, "## New Contributors") || strings.HasPrefix(line, "**Full Changelog**") {
+			break
+		}
+
+		matches := _releaseNoteRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		change := ParseCommit(matches[1])
+		change.Author = matches[2]
+		if matches[4] != "" {
+			change.PullRequest, _ = strconv.Atoi(matches[4])
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func (c *Change) String() string {
+	var builder strings.Builder
+	if c.Scope != "" {
+		fmt.Fprintf(&builder, "%s: ", c.Scope)
+	}
+	builder.WriteString(c.Description)
+	if c.PullRequest != 0 {
+		fmt.Fprintf(&builder, " (#%d)", c.PullRequest)
+	}
+	if c.Author != "" {
+		fmt.Fprintf(&builder, " @%s", c.Author)
+	}
+	return builder.String()
+}
+
+// Groups returns the changes grouped by type, breaking changes are always listed first
+func (c *Changelog) Groups() []ChangeGroup {
+	var breaking []*Change
+	byType := make(map[string][]*Change)
+	for _, change := range c.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+			continue
+		}
+		changeType := change.Type
+		if !isGroupedType(changeType) {
+			changeType = ChangeOther
+		}
+		byType[changeType] = append(byType[changeType], change)
+	}
+
+	groups := make([]ChangeGroup, 0, len(_changeGroups)+1)
+	if len(breaking) > 0 {
+		groups = append(groups, ChangeGroup{Title: "BREAKING CHANGES", Changes: breaking})
+	}
+	for _, group := range _changeGroups {
+		if changes, ok := byType[group.Type]; ok {
+			groups = append(groups, ChangeGroup{Title: group.Title, Changes: changes})
+		}
+	}
+	return groups
+}
+
+// Summary counts the changes by type, e.g. "5 changes: 2 feat, 3 fix (1 breaking)"
+func (c *Changelog) Summary() string {
+	if len(c.Changes) == 0 {
+		return "No changes"
+	}
+
+	counts := make(map[string]int)
+	breaking := 0
+	for _, change := range c.Changes {
+		changeType := change.Type
+		if !isGroupedType(changeType) {
+			changeType = ChangeOther
+		}
+		counts[changeType]++
+		if change.Breaking {
+			breaking++
+		}
+	}
+
+	types := make([]string, 0, len(counts))
+	for _, group := range _changeGroups {
+		if count, ok := counts[group.Type]; ok {
+			types = append(types, fmt.Sprintf("%d %s", count, group.Type))
+		}
+	}
+
+	summary := fmt.Sprintf("%d %s: %s", len(c.Changes), plural(len(c.Changes), "change"), strings.Join(types, ", "))
+	if breaking > 0 {
+		summary += fmt.Sprintf(" (%d breaking)", breaking)
+	}
+	return summary
+}
+
+func plural(count int, word string) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+func (c *Changelog) String() string {
+	var builder strings.Builder
+	for _, note := range c.Notes {
+		fmt.Fprintf(&builder, "Note: %s\n", note)
+	}
+
+	if len(c.Changes) == 0 {
+		if !c.Removed {
+			builder.WriteString("No changes\n")
+		}
+		return builder.String()
+	}
+
+	for _, group := range c.Groups() {
+		fmt.Fprintf(&builder, "%s\n", group.Title)
+		for _, change := range group.Changes {
+			fmt.Fprintf(&builder, "- %s\n", change)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// PullRequestURL returns the url of the change's pull request, or of its commit when it was not merged via pull request
+func (c *Changelog) PullRequestURL(change *Change) string {
+	switch {
+	case change.PullRequest != 0:
+		return fmt.Sprintf("https://github.com/%s/%s/pull/%d", c.Org, c.Repo, change.PullRequest)
+	case change.SHA != "":
+		return fmt.Sprintf("https://github.com/%s/%s/commit/%s", c.Org, c.Repo, change.SHA)
+	}
+	return ""
+}
+
+// Repos returns the repo names sorted alphabetically
+func (c Changelogs) Repos() []string {
+	repos := make([]string, 0, len(c))
+	for repo := range c {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+func (c Changelogs) String() string {
+	var builder strings.Builder
+	for _, repo := range c.Repos() {
+		fmt.Fprintf(&builder, "\nService Repo: %s\n", repo)
+		builder.WriteString(c[repo].String())
+	}
+	return builder.String()
+}
+
+func isGroupedType(changeType string) bool {
+	for _, group := range _changeGroups {
+		if group.Type == changeType {
+			return true
+		}
+	}
+	return false
+}
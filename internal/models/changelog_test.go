@@ -0,0 +1,149 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommit(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		message string
+		want    *Change
+	}{
+		{
+			name:    "conventional_with_scope",
+			message: "feat(api): add applications endpoint (#12)",
+			want: &Change{Type: ChangeFeat, Scope: "api", Description: "add applications endpoint", PullRequest: 12,
+				Message: "feat(api): add applications endpoint (#12)"},
+		},
+		{
+			name:    "breaking_bang",
+			message: "fix!: drop legacy auth",
+			want:    &Change{Type: ChangeFix, Description: "drop legacy auth", Breaking: true, Message: "fix!: drop legacy auth"},
+		},
+		{
+			name:    "breaking_footer",
+			message: "perf: cache lookups\n\nBREAKING CHANGE: cache must be configured",
+			want: &Change{Type: ChangePerf, Description: "cache lookups", Breaking: true,
+				Message: "perf: cache lookups\n\nBREAKING CHANGE: cache must be configured"},
+		},
+		{
+			name:    "merge_commit",
+			message: "Merge pull request #34 from dividohq/fix/ING-12\n\nfix: handle empty lenders",
+			want: &Change{Type: ChangeFix, Description: "handle empty lenders", PullRequest: 34,
+				Message: "Merge pull request #34 from dividohq/fix/ING-12\n\nfix: handle empty lenders"},
+		},
+		{
+			name:    "not_conventional",
+			message: "Update README",
+			want:    &Change{Type: ChangeOther, Description: "Update README", Message: "Update README"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCommit(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommit() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReleaseNotes(t *testing.T) {
+
+	body := `## What's Changed
+* feat: add lenders by @jane in https://github.com/dividohq/api/pull/7
+* chore(deps): bump yaml by @dependabot in https://github.com/dividohq/api/pull/8
+
+## New Contributors
+* @jane made their first contribution in https://github.com/dividohq/api/pull/7
+
+**Full Changelog**: https://github.com/dividohq/api/compare/v1.0.0...v1.1.0`
+
+	changes := ParseReleaseNotes(body)
+	if len(changes) != 2 {
+		t.Fatalf("ParseReleaseNotes() got %d changes, want 2", len(changes))
+	}
+
+	want := &Change{Type: ChangeChore, Scope: "deps", Description: "bump yaml", PullRequest: 8, Author: "dependabot",
+		Message: "chore(deps): bump yaml"}
+	if !reflect.DeepEqual(changes[1], want) {
+		t.Errorf("ParseReleaseNotes() got = %+v, want %+v", changes[1], want)
+	}
+}
+
+func TestChangelog_Groups(t *testing.T) {
+
+	changelog := Changelog{Changes: []*Change{
+		{Type: ChangeFix, Description: "a"},
+		{Type: "docs", Description: "b"},
+		{Type: ChangeFeat, Description: "c", Breaking: true},
+		{Type: ChangeFeat, Description: "d"},
+	}}
+
+	var titles []string
+	for _, group := range changelog.Groups() {
+		titles = append(titles, group.Title)
+	}
+
+	want := []string{"BREAKING CHANGES", "Features", "Bug Fixes", "Other Changes"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("Groups() got = %v, want %v", titles, want)
+	}
+}
+
+func TestChangelog_Summary(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []*Change
+		want    string
+	}{
+		{"empty", nil, "No changes"},
+		{"single", []*Change{{Type: ChangeFix}}, "1 change: 1 fix"},
+		{"grouped", []*Change{
+			{Type: ChangeFix},
+			{Type: "docs"},
+			{Type: ChangeFeat, Breaking: true},
+			{Type: ChangeFeat},
+		}, "4 changes: 2 feat, 1 fix, 1 other (1 breaking)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changelog := Changelog{Changes: tt.changes}
+			if got := changelog.Summary(); got != tt.want {
+				t.Errorf("Summary() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangelogs_Tickets(t *testing.T) {
+
+	changelogs := Changelogs{
+		"api": {Org: "dividohq", Repo: "api", Changes: []*Change{
+			{Description: "ING-12 add lenders", PullRequest: 3},
+			{Description: "handle empty lenders", PullRequest: 4, Message: "Merge pull request #4 from dividohq/fix/ing-12"},
+			{Description: "unrelated UTF-8 fix", SHA: "0123456789"},
+		}},
+		"portals-web-pub": {Org: "dividohq", Repo: "portals-web-pub", Changes: []*Change{
+			{Description: "DIV-7: ING-12 banner", PullRequest: 9},
+		}},
+	}
+
+	tickets := changelogs.Tickets(JiraConfig{URL: "https://divido.atlassian.net/", Projects: []string{"ING", "DIV"}})
+
+	want := Tickets{
+		{Key: "DIV-7", URL: "https://divido.atlassian.net/browse/DIV-7", Refs: []TicketRef{
+			{Repo: "portals-web-pub", PullRequest: 9, URL: "https://github.com/dividohq/portals-web-pub/pull/9"},
+		}},
+		{Key: "ING-12", URL: "https://divido.atlassian.net/browse/ING-12", Refs: []TicketRef{
+			{Repo: "api", PullRequest: 3, URL: "https://github.com/dividohq/api/pull/3"},
+			{Repo: "api", PullRequest: 4, URL: "https://github.com/dividohq/api/pull/4"},
+			{Repo: "portals-web-pub", PullRequest: 9, URL: "https://github.com/dividohq/portals-web-pub/pull/9"},
+		}},
+	}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("Tickets() got = %v, want %v", tickets, want)
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"github.com/mgutz/ansi"
+	"sort"
 	"strings"
 )
 
@@ -12,6 +13,8 @@ type ServiceUpdated struct {
 }
 
 type Comparer struct {
+	Platform       string
+	Repo           string
 	InitialVersion string
 	FinalVersion   string
 	Insert         Services
@@ -20,6 +23,16 @@ type Comparer struct {
 	DisableColor   bool
 }
 
+// ChangedNames returns the names of the changed services sorted
+func (c *Comparer) ChangedNames() []string {
+	names := make([]string, 0, len(c.Changed))
+	for name := range c.Changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func Compare(plat1, plat2 *Platform) *Comparer {
 
 	changed := make(map[string]*ServiceUpdated)
@@ -97,8 +110,12 @@ func (c *Comparer) String() string {
 
 	if len(c.Deleted) > 0 {
 		builder.WriteString("Service Versions Excluded:\n")
-		for k, i := range c.Insert {
-			fmt.Fprintf(&builder, " %s: %s\n", k, c.MakeDiffText(i.Version, "red"))
+		for k, d := range c.Deleted {
+			fmt.Fprintf(&builder, " %s: %s (removed in this release)", k, c.MakeDiffText(d.Version, "red"))
+			if d.URL != "" {
+				fmt.Fprintf(&builder, " %s", d.URL)
+			}
+			builder.WriteString("\n")
 		}
 	}
 
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestComparer_String(t *testing.T) {
+
+	comparer := Comparer{
+		InitialVersion: "v1.0.0",
+		FinalVersion:   "v1.1.0",
+		Insert:         Services{"lenderApi": {HLMName: "lenderApi", Release: Release{Version: "v0.1.0"}}},
+		Deleted:        Services{"legacyApi": {HLMName: "legacyApi", Release: Release{Version: "v2.3.0", URL: "url"}}},
+		DisableColor:   true,
+	}
+
+	want := `Base Helm Chart Change: v1.0.0 -> v1.1.0
+
+Service Versions Included:
+ lenderApi: v0.1.0
+Service Versions Excluded:
+ legacyApi: v2.3.0 (removed in this release) url
+`
+	if got := comparer.String(); got != want {
+		t.Errorf("String() got = %v, want %v", got, want)
+	}
+}
@@ -1,32 +1,80 @@
 package models
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
 type Config struct {
-	Platforms       []PlatformConfig
-	Github          GithubConfig
-	ServicesMapping map[string]ServiceMapping `mapstructure:"services"`
+	// Schema is the JSON Schema the config file is written against
+	Schema    string           `mapstructure:"$schema" json:"$schema,omitempty"`
+	Platforms []PlatformConfig `json:"platforms,omitempty"`
+	Github    GithubConfig     `json:"github,omitempty"`
+	Jira      JiraConfig       `json:"jira,omitempty"`
+	Export    ExportConfig     `json:"export,omitempty"`
+	Services  ServiceRules     `mapstructure:"services" json:"services,omitempty"`
 }
 
 type ServiceMapping struct {
-	Repo     string
-	MultiTag bool
+	Repo     string `json:"repo,omitempty"`
+	MultiTag bool   `json:"multiTag,omitempty"`
+	// TagPrefix is a text/template of the tag prefix of multi tag services, it receives the service
+	// .Name and defaults to "{{ .Name | kebab }}"
+	TagPrefix string `json:"tagPrefix,omitempty"`
+	// TagSeparator separates the tag prefix from the version, defaults to "-"
+	TagSeparator string `json:"tagSeparator,omitempty"`
+	// Baseline is the version the changelog of a newly added service starts from, defaults to its first release
+	Baseline string `json:"baseline,omitempty"`
+
+	// prefix is the parsed TagPrefix, see CompilePrefix
+	prefix *template.Template
 }
 
 type GithubConfig struct {
-	Org                      string
-	AuthorName               string
-	AuthorEmail              string
-	MainBranch               string
-	Message                  string
-	PreCommitMessage         string
-	CommitMessageBumpHc      string
-	CommitMessageBumpService string
+	Org                      string `json:"org,omitempty"`
+	AuthorName               string `json:"authorName,omitempty"`
+	AuthorEmail              string `json:"authorEmail,omitempty"`
+	MainBranch               string `json:"mainBranch,omitempty"`
+	Message                  string `json:"message,omitempty"`
+	PreCommitMessage         string `json:"preCommitMessage,omitempty"`
+	CommitMessageBumpHc      string `json:"commitMessageBumpHc,omitempty"`
+	CommitMessageBumpService string `json:"commitMessageBumpService,omitempty"`
+	// Concurrency is the number of GitHub requests made at once, 0 uses the default
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+type JiraConfig struct {
+	URL      string   `json:"url,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+}
+
+type ExportConfig struct {
+	// Format is the format selected by default when exporting a release
+	Format string `json:"format,omitempty"`
+	// Dir is a text/template of the directory the release is exported to
+	Dir string `json:"dir,omitempty"`
+	// Archive is the archive type (tar.gz or zip) selected by default, empty exports a folder
+	Archive string `json:"archive,omitempty"`
+	// Formats overrides the built-in formats or adds new ones, keyed by format name
+	Formats map[string]ExportFormatConfig `json:"formats,omitempty"`
+}
+
+type ExportFormatConfig struct {
+	// Template is the path of a template file defining the "release" and "changelog" templates
+	Template          string `json:"template,omitempty"`
+	FileName          string `json:"fileName,omitempty"`
+	ChangelogFileName string `json:"changelogFileName,omitempty"`
+	// HTML renders the templates with html/template instead of text/template
+	HTML bool `mapstructure:"html" json:"html,omitempty"`
 }
 
 type PlatformConfig struct {
-	Name          string
-	HelmChartRepo string `mapstructure:"hlm"`
-	Envs          []EnvironmentConfig
-	DirectCommit  bool
+	Name          string              `json:"name,omitempty"`
+	HelmChartRepo string              `mapstructure:"hlm" json:"hlm,omitempty"`
+	Envs          []EnvironmentConfig `json:"envs,omitempty"`
+	DirectCommit  bool                `json:"directCommit,omitempty"`
 }
 
 type ServicesConfig struct {
@@ -35,11 +83,11 @@ type ServicesConfig struct {
 }
 
 type EnvironmentConfig struct {
-	Name          string
-	Repo          string
-	ChartPath     string `mapstructure:",omitempty"`
-	DirectCommit  bool
-	OnlyOverrides bool
+	Name          string `json:"name,omitempty"`
+	Repo          string `json:"repo,omitempty"`
+	ChartPath     string `mapstructure:",omitempty" json:"chartPath,omitempty"`
+	DirectCommit  bool   `json:"directCommit,omitempty"`
+	OnlyOverrides bool   `json:"onlyOverrides,omitempty"`
 }
 
 func (c Config) ListPlatform() []string {
@@ -79,3 +127,72 @@ func (p *PlatformConfig) GetEnvironment(envIndex int) *EnvironmentConfig {
 	}
 	return &p.Envs[envIndex]
 }
+
+// Validate checks the platforms and compiles the service rules, every problem found is reported
+func (c Config) Validate() error {
+	var problems []string
+
+	platforms := make(map[string]bool, len(c.Platforms))
+	for i, platform := range c.Platforms {
+		switch {
+		case platform.Name == "":
+			problems = append(problems, fmt.Sprintf("platform %d: name is required", i+1))
+		case platforms[platform.Name]:
+			problems = append(problems, fmt.Sprintf("platform %s: name is duplicated", platform.Name))
+		}
+		platforms[platform.Name] = true
+
+		if platform.HelmChartRepo == "" {
+			problems = append(problems, fmt.Sprintf("platform %s: hlm is required", platform.Name))
+		}
+		for j, env := range platform.Envs {
+			if env.Name == "" {
+				problems = append(problems, fmt.Sprintf("platform %s env %d: name is required", platform.Name, j+1))
+			}
+			if env.Repo == "" {
+				problems = append(problems, fmt.Sprintf("platform %s env %s: repo is required", platform.Name, env.Name))
+			}
+		}
+	}
+
+	if c.Github.Concurrency < 0 {
+		problems = append(problems, "github: concurrency must not be negative")
+	}
+
+	problems = append(problems, c.Services.compile()...)
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid config:\n %s", strings.Join(problems, "\n "))
+	}
+	return nil
+}
+
+// Repos returns every repo of the platforms and service rules, sorted and without duplicates
+func (c Config) Repos() []string {
+	seen := make(map[string]bool)
+	add := func(repo string) {
+		if repo != "" {
+			seen[repo] = true
+		}
+	}
+
+	for _, platform := range c.Platforms {
+		add(platform.HelmChartRepo)
+		for _, env := range platform.Envs {
+			add(env.Repo)
+		}
+	}
+	for _, rule := range c.Services {
+		if !rule.Ignore {
+			add(strings.ToLower(rule.Repo))
+		}
+	}
+
+	repos := make([]string, 0, len(seen))
+	for repo := range seen {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
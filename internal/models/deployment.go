@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Deployment is the version of a service running in an env, or in the latest chart of a platform when Env is empty
+type Deployment struct {
+	Platform string
+	Env      string
+	Service  string
+	Version  string
+	// Override is set when the env overrides the version of the chart
+	Override bool
+	// Behind is the number of releases between the version and the latest one, -1 when the version is not released
+	Behind int
+	// BehindErr is set when the releases of the service could not be fetched, Behind is then unknown
+	BehindErr error
+	// Since is when the env got the version, from the history of its version files, zero when unknown
+	Since time.Time
+	// SinceOlder is set when the version was already running at the oldest change looked at, the env got it at
+	// Since or before
+	SinceOlder bool
+	// First is set on the env that got the version first, see MarkFirst
+	First bool
+	// Err is set when the env could not be loaded
+	Err error
+}
+
+type Deployments []*Deployment
+
+// MarkFirst marks the env that got each version of each service first, across platforms. A version is not
+// marked when its first env cannot be told, i.e. one of its envs has no Since or may have got it earlier
+func (deployments Deployments) MarkFirst() {
+	envs := make(map[string]Deployments)
+	var keys []string
+	for _, d := range deployments {
+		if d.Env == "" || d.Err != nil {
+			continue
+		}
+		key := d.Service + "@" + d.Version
+		if _, ok := envs[key]; !ok {
+			keys = append(keys, key)
+		}
+		envs[key] = append(envs[key], d)
+	}
+
+	for _, key := range keys {
+		var first *Deployment
+		known := true
+		for _, d := range envs[key] {
+			if d.Since.IsZero() {
+				known = false
+				break
+			}
+			if first == nil || d.Since.Before(first.Since) {
+				first = d
+			}
+		}
+		if !known {
+			continue
+		}
+		// an env running the version since before the date it was looked back to may have got it earlier
+		for _, d := range envs[key] {
+			if d != first && d.SinceOlder {
+				known = false
+			}
+		}
+		if known {
+			first.First = true
+		}
+	}
+}
+
+func (deployments Deployments) String() string {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLATFORM\tENV\tSERVICE\tVERSION\tBEHIND\tSINCE\t")
+	for _, d := range deployments {
+		env := d.Env
+		if env == "" {
+			env = "(latest chart)"
+		}
+		if d.Err != nil {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\t\t\n", d.Platform, env, d.Service, d.Err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", d.Platform, env, d.Service, d.Version, d.behind(), d.since(), d.marks())
+	}
+	w.Flush()
+	return builder.String()
+}
+
+func (d Deployment) behind() string {
+	switch {
+	case d.BehindErr != nil:
+		return "unknown"
+	case d.Behind < 0:
+		return "unreleased"
+	case d.Behind == 0:
+		return "latest"
+	}
+	return fmt.Sprintf("%d behind", d.Behind)
+}
+
+// since is the date the env got the version, empty on the latest chart of a platform
+func (d Deployment) since() string {
+	switch {
+	case d.Env == "":
+		return ""
+	case d.Since.IsZero():
+		return "unknown"
+	case d.SinceOlder:
+		return "by " + d.Since.Format("2006-01-02")
+	}
+	return d.Since.Format("2006-01-02")
+}
+
+func (d Deployment) marks() string {
+	var marks []string
+	if d.Override {
+		marks = append(marks, "override")
+	}
+	if d.First {
+		marks = append(marks, "first")
+	}
+	return strings.Join(marks, ", ")
+}
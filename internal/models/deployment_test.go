@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeployments_MarkFirst(t *testing.T) {
+
+	date := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	deployments := Deployments{
+		{Platform: "ing", Service: "applicationApi", Version: "v1.1.0"},
+		{Platform: "ing", Env: "test", Service: "applicationApi", Version: "v1.1.0", Since: date.Add(2 * day)},
+		{Platform: "ing", Env: "staging", Service: "applicationApi", Err: errors.New("not found")},
+		{Platform: "ing", Env: "sandbox", Service: "applicationApi", Version: "v1.1.0", Since: date},
+		{Platform: "ing", Env: "prod", Service: "applicationApi", Version: "v1.0.0", Since: date.Add(5 * day)},
+		{Platform: "divido", Env: "prod", Service: "applicationApi", Version: "v1.0.0", Since: date.Add(3 * day)},
+		{Platform: "ing", Env: "test", Service: "portalsWebPub", Version: "v1.2.0", Since: date.Add(day)},
+		{Platform: "divido", Env: "test", Service: "portalsWebPub", Version: "v1.2.0"},
+		{Platform: "ing", Env: "test", Service: "worker", Version: "v1.3.0", Since: date.Add(day)},
+		{Platform: "ing", Env: "prod", Service: "worker", Version: "v1.3.0", Since: date.Add(4 * day), SinceOlder: true},
+		{Platform: "ing", Env: "test", Service: "lenderApi", Version: "v1.4.0", Since: date.Add(4 * day), SinceOlder: true},
+		{Platform: "ing", Env: "prod", Service: "lenderApi", Version: "v1.4.0", Since: date.Add(5 * day)},
+	}
+	deployments.MarkFirst()
+
+	want := []bool{false, false, false, true, false, true, false, false, false, false, true, false}
+	for i, d := range deployments {
+		if d.First != want[i] {
+			t.Errorf("MarkFirst() %s/%s %s got = %v, want %v", d.Platform, d.Env, d.Service, d.First, want[i])
+		}
+	}
+}
+
+func TestDeployments_String(t *testing.T) {
+
+	date := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	deployments := Deployments{
+		{Platform: "ing", Service: "applicationApi", Version: "v1.1.0"},
+		{Platform: "ing", Env: "test", Service: "applicationApi", Version: "v1.1.0", Override: true, First: true, Since: date},
+		{Platform: "ing", Env: "prod", Service: "applicationApi", Version: "v1.0.0", Behind: 2, Since: date, SinceOlder: true},
+		{Platform: "ing", Env: "dev", Service: "applicationApi", Version: "feature", Behind: -1},
+		{Platform: "ing", Env: "demo", Service: "applicationApi", Version: "v1.0.0", BehindErr: errors.New("no releases"), Since: date},
+	}
+
+	want := []string{
+		"PLATFORM  ENV             SERVICE         VERSION  BEHIND      SINCE",
+		"ing       (latest chart)  applicationApi  v1.1.0   latest",
+		"ing       test            applicationApi  v1.1.0   latest      2022-06-01     override, first",
+		"ing       prod            applicationApi  v1.0.0   2 behind    by 2022-06-01",
+		"ing       dev             applicationApi  feature  unreleased  unknown",
+		"ing       demo            applicationApi  v1.0.0   unknown     2022-06-01",
+	}
+	got := strings.Split(strings.TrimSuffix(deployments.String(), "\n"), "\n")
+	for i := range got {
+		got[i] = strings.TrimRight(got[i], " ")
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("String() got = \n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	_helmChartRepoSuffix = "-hlm"
+	_envRepoSuffix       = "-inf"
+	_platformSuffix      = "-platform"
+)
+
+// _envOrder sorts the discovered environments in the order they are deployed to, others go last
+var _envOrder = map[string]int{"dev": 0, "test": 1, "stag": 2, "uat": 3, "sbx": 4, "prod": 5}
+
+// DiscoverPlatforms proposes a platform for each helm chart repo (e.g. ing-platform-hlm) with the
+// environment repos sharing its prefix (e.g. ing-platform-test-inf), repos matching no platform are ignored
+func DiscoverPlatforms(names []string) []PlatformConfig {
+	repos := make([]string, len(names))
+	copy(repos, names)
+	sort.Strings(repos)
+
+	var platforms []PlatformConfig
+	for _, repo := range repos {
+		if !strings.HasSuffix(repo, _helmChartRepoSuffix) {
+			continue
+		}
+
+		prefix := strings.TrimSuffix(repo, _helmChartRepoSuffix)
+		platform := PlatformConfig{
+			Name:          strings.TrimSuffix(prefix, _platformSuffix),
+			HelmChartRepo: repo,
+		}
+
+		for _, envRepo := range repos {
+			if !strings.HasPrefix(envRepo, prefix+"-") || !strings.HasSuffix(envRepo, _envRepoSuffix) {
+				continue
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(envRepo, prefix+"-"), _envRepoSuffix)
+			if name == "" || strings.Contains(name, "-") {
+				// belongs to a platform with a longer prefix, e.g. ing-platform-eu-test-inf
+				continue
+			}
+			platform.Envs = append(platform.Envs, EnvironmentConfig{Name: name, Repo: envRepo})
+		}
+
+		sort.SliceStable(platform.Envs, func(i, j int) bool {
+			return envOrder(platform.Envs[i].Name) < envOrder(platform.Envs[j].Name)
+		})
+		platforms = append(platforms, platform)
+	}
+	return platforms
+}
+
+func envOrder(name string) int {
+	if order, ok := _envOrder[name]; ok {
+		return order
+	}
+	return len(_envOrder)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverPlatforms(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		repos []string
+		want  []PlatformConfig
+	}{
+		{
+			name: "platforms_and_envs",
+			repos: []string{"ing-platform-prod-inf", "application-api", "ing-platform-hlm", "ing-platform-test-inf",
+				"ing-platform-stag-inf", "divido-platform-hlm", "divido-platform-sbx-inf", "test-k8s-services-inf"},
+			want: []PlatformConfig{
+				{Name: "divido", HelmChartRepo: "divido-platform-hlm", Envs: []EnvironmentConfig{
+					{Name: "sbx", Repo: "divido-platform-sbx-inf"},
+				}},
+				{Name: "ing", HelmChartRepo: "ing-platform-hlm", Envs: []EnvironmentConfig{
+					{Name: "test", Repo: "ing-platform-test-inf"},
+					{Name: "stag", Repo: "ing-platform-stag-inf"},
+					{Name: "prod", Repo: "ing-platform-prod-inf"},
+				}},
+			},
+		},
+		{
+			name:  "longer_prefix",
+			repos: []string{"ing-hlm", "ing-eu-hlm", "ing-eu-test-inf", "ing-qa-inf"},
+			want: []PlatformConfig{
+				{Name: "ing-eu", HelmChartRepo: "ing-eu-hlm", Envs: []EnvironmentConfig{{Name: "test", Repo: "ing-eu-test-inf"}}},
+				{Name: "ing", HelmChartRepo: "ing-hlm", Envs: []EnvironmentConfig{{Name: "qa", Repo: "ing-qa-inf"}}},
+			},
+		},
+		{
+			name:  "no_platforms",
+			repos: []string{"application-api"},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := append([]string(nil), tt.repos...)
+			if got := DiscoverPlatforms(repos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiscoverPlatforms() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(repos, tt.repos) {
+				t.Errorf("DiscoverPlatforms() sorted its input to %v", repos)
+			}
+		})
+	}
+}
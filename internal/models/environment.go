@@ -20,6 +20,18 @@ func (env Environment) String() string {
 	return fmt.Sprintf("Name: %s\nhelm version: %s\n", env.Name, env.HelmChartVersion)
 }
 
+// Deployed returns the services of the chart with the overrides of the env applied
+func (env Environment) Deployed() Services {
+	deployed := make(Services, len(env.Services)+len(env.Overrides))
+	for name, service := range env.Services {
+		deployed[name] = service
+	}
+	for name, service := range env.Overrides {
+		deployed[name] = service
+	}
+	return deployed
+}
+
 func (env Environment) GetHCVersion() string {
 	return fmt.Sprintf("v%s", strings.TrimSpace(env.HelmChartVersion))
 }
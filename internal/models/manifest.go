@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ServiceChanged = "changed"
+	ServiceAdded   = "added"
+	ServiceRemoved = "removed"
+)
+
+// Manifest describes the content of an exported release archive
+type Manifest struct {
+	Platform  string            `json:"platform"`
+	Repo      string            `json:"repo"`
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Services  []ManifestService `json:"services"`
+	Sources   []ManifestSource  `json:"sources"`
+	Exporter  string            `json:"exporter"`
+	Timestamp time.Time         `json:"timestamp"`
+	// Files holds the sha256 checksum of every file in the archive, keyed by file name
+	Files map[string]string `json:"files"`
+}
+
+type ManifestService struct {
+	Name   string `json:"name"`
+	Change string `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type ManifestSource struct {
+	Repo string `json:"repo"`
+	Ref  string `json:"ref"`
+	SHA  string `json:"sha"`
+}
+
+func (m Manifest) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, " Platform: %s (%s)\n Versions: %s -> %s\n Exported by %s at %s\n", m.Platform, m.Repo, m.From, m.To,
+		m.Exporter, m.Timestamp.Format(time.RFC3339))
+	for _, source := range m.Sources {
+		fmt.Fprintf(&builder, " Source: %s@%s %s\n", source.Repo, source.Ref, source.SHA)
+	}
+	fmt.Fprintf(&builder, " Services: %d\n Files: %d\n", len(m.Services), len(m.Files))
+	return builder.String()
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"github.com/gobeam/stringy"
+	"strings"
+	"text/template"
+)
+
+const (
+	_defaultTagPrefix    = "{{ .Name | kebab }}"
+	_defaultTagSeparator = "-"
+)
+
+var _tagPrefixFuncs = template.FuncMap{
+	"kebab": func(s string) string { return stringy.New(s).KebabCase().ToLower() },
+	"snake": func(s string) string { return stringy.New(s).SnakeCase().ToLower() },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+var _defaultPrefix = template.Must(template.New("").Funcs(_tagPrefixFuncs).Parse(_defaultTagPrefix))
+
+// Prefix returns the prefix of the service tags in a multi tag repo, e.g. "lender-graphql-api-",
+// services in single tag repos have no prefix
+func (m ServiceMapping) Prefix(serviceName string) string {
+	if !m.MultiTag {
+		return ""
+	}
+
+	prefix, err := m.renderPrefix(serviceName)
+	if err != nil {
+		// an invalid or not compiled template is reported by the config validation, the default prefix is used meanwhile
+		prefix = stringy.New(serviceName).KebabCase().ToLower()
+	}
+
+	return prefix + m.separator()
+}
+
+func (m ServiceMapping) separator() string {
+	if m.TagSeparator == "" {
+		return _defaultTagSeparator
+	}
+	return m.TagSeparator
+}
+
+// Tag returns the repo tag of a service version
+func (m ServiceMapping) Tag(serviceName, version string) string {
+	return m.Prefix(serviceName) + version
+}
+
+// Version returns the service version of a repo tag, it is false when the tag belongs to another service
+func (m ServiceMapping) Version(serviceName, tag string) (string, bool) {
+	prefix := m.Prefix(serviceName)
+	if !strings.HasPrefix(tag, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(tag, prefix), true
+}
+
+// CompilePrefix parses the tag prefix template and checks it renders, it is called by ServiceRules.Compile
+func (m *ServiceMapping) CompilePrefix() error {
+	m.prefix = nil
+	if m.TagPrefix == "" {
+		return nil
+	}
+
+	tpl, err := template.New("").Funcs(_tagPrefixFuncs).Parse(m.TagPrefix)
+	if err == nil {
+		_, err = executePrefix(tpl, "serviceName")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid tag prefix %q %w", m.TagPrefix, err)
+	}
+	m.prefix = tpl
+	return nil
+}
+
+func (m ServiceMapping) renderPrefix(serviceName string) (string, error) {
+	tpl := m.prefix
+	if tpl == nil {
+		if m.TagPrefix != "" {
+			return "", fmt.Errorf("tag prefix %q is not compiled", m.TagPrefix)
+		}
+		tpl = _defaultPrefix
+	}
+	return executePrefix(tpl, serviceName)
+}
+
+func executePrefix(tpl *template.Template, serviceName string) (string, error) {
+	var builder strings.Builder
+	if err := tpl.Execute(&builder, struct{ Name string }{Name: serviceName}); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
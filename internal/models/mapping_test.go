@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestServiceMapping_Tag(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		mapping     ServiceMapping
+		serviceName string
+		tag         string
+		wantTag     string
+		wantVersion string
+		wantOk      bool
+	}{
+		{name: "single_tag", mapping: ServiceMapping{Repo: "api"}, serviceName: "applicationApi",
+			tag: "v1.0.0", wantTag: "v1.0.0", wantVersion: "v1.0.0", wantOk: true},
+		{name: "default_prefix", mapping: ServiceMapping{Repo: "graphql-apis", MultiTag: true}, serviceName: "lenderGraphqlApi",
+			tag: "lender-graphql-api-v1.0.0", wantTag: "lender-graphql-api-v1.0.0", wantVersion: "v1.0.0", wantOk: true},
+		{name: "other_service_tag", mapping: ServiceMapping{Repo: "graphql-apis", MultiTag: true}, serviceName: "lenderGraphqlApi",
+			tag: "applicant-graphql-api-v1.0.0", wantTag: "lender-graphql-api-v1.0.0", wantOk: false},
+		{name: "custom_prefix", mapping: ServiceMapping{Repo: "graphql-apis", MultiTag: true, TagPrefix: "{{ .Name | snake }}", TagSeparator: "/"},
+			serviceName: "lenderGraphqlApi", tag: "lender_graphql_api/v1.0.0", wantTag: "lender_graphql_api/v1.0.0", wantVersion: "v1.0.0", wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mapping.CompilePrefix(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.mapping.Tag(tt.serviceName, "v1.0.0"); got != tt.wantTag {
+				t.Errorf("Tag() got = %v, want %v", got, tt.wantTag)
+			}
+			version, ok := tt.mapping.Version(tt.serviceName, tt.tag)
+			if version != tt.wantVersion || ok != tt.wantOk {
+				t.Errorf("Version() got = %v %v, want %v %v", version, ok, tt.wantVersion, tt.wantOk)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -9,11 +11,15 @@ type Releases []*Release
 type Versions []string
 
 type Release struct {
-	Name      string
-	Version   string
+	Name    string
+	Version string
+	// Tag is the repo tag of the release, it only differs from the version on multi tag repos
+	Tag       string
 	Changelog string
 	URL       string
 	Date      time.Time
+	// Prerelease is set when the release is marked as a prerelease on GitHub
+	Prerelease bool
 }
 
 func (releases Releases) String() string {
@@ -41,10 +47,110 @@ func (releases Releases) GetReleaseByVersion(version string) *Release {
 	return nil
 }
 
+// Latest returns the last published release, prereleases are only returned when there is no other release
+func (releases Releases) Latest() *Release {
+	var latest *Release
+	for _, r := range releases {
+		switch {
+		case latest == nil,
+			latest.IsPrerelease() && !r.IsPrerelease(),
+			latest.IsPrerelease() == r.IsPrerelease() && r.Date.After(latest.Date):
+			latest = r
+		}
+	}
+	return latest
+}
+
+// Sort sorts the releases by semver descending, the versions that are not semver are last by date descending
+func (releases Releases) Sort() {
+	sort.SliceStable(releases, func(i, j int) bool {
+		if c := CompareVersions(releases[i].Version, releases[j].Version); c != 0 {
+			return c > 0
+		}
+		return releases[i].Date.After(releases[j].Date)
+	})
+}
+
+// Labels returns the versions marked as deployed, latest or prerelease, e.g. "v1.2.0 (deployed, latest)"
+func (releases Releases) Labels(deployed string) []string {
+	latest := releases.Latest()
+
+	labels := make([]string, 0, len(releases))
+	for _, r := range releases {
+		var marks []string
+		if deployed != "" && r.Version == deployed {
+			marks = append(marks, "deployed")
+		}
+		if r == latest {
+			marks = append(marks, "latest")
+		}
+		if r.IsPrerelease() {
+			marks = append(marks, "prerelease")
+		}
+
+		label := r.Version
+		if len(marks) > 0 {
+			label = fmt.Sprintf("%s (%s)", r.Version, strings.Join(marks, ", "))
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// Behind returns the number of releases published after the version, prereleases are not counted. It is -1 when
+// the version is not one of the releases
+func (releases Releases) Behind(version string) int {
+	current := releases.GetReleaseByVersion(version)
+	if current == nil {
+		return -1
+	}
+
+	behind := 0
+	for _, r := range releases {
+		if !r.IsPrerelease() && r.Date.After(current.Date) {
+			behind++
+		}
+	}
+	return behind
+}
+
+// Oldest returns the first published release
+func (releases Releases) Oldest() *Release {
+	var oldest *Release
+	for _, r := range releases {
+		if oldest == nil || r.Date.Before(oldest.Date) {
+			oldest = r
+		}
+	}
+	return oldest
+}
+
 func (release Release) String() string {
 	return fmt.Sprintf(" Name: %s\n latest version: %s\n URL: %s\n", release.Name, release.Version, release.URL)
 }
 
+// IsPrerelease reports whether the release is marked as a prerelease or its version is a semver prerelease
+func (release Release) IsPrerelease() bool {
+	return release.Prerelease || IsPrerelease(release.Version)
+}
+
+// Summary returns the first lines of the changelog, blank lines are skipped
+func (release Release) Summary(lines int) string {
+	summary := make([]string, 0, lines)
+	for _, line := range strings.Split(release.Changelog, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if len(summary) == lines {
+			summary = append(summary, "...")
+			break
+		}
+		summary = append(summary, line)
+	}
+	return strings.Join(summary, "\n")
+}
+
 func (versions *Versions) Remove(index int) {
 	copy((*versions)[index:], (*versions)[index+1:]) // shift valuesafter the indexwith a factor of 1
 	(*versions)[len(*versions)-1] = ""               // remove element
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testReleases() Releases {
+	date := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	return Releases{
+		{Version: "v1.9.0", Date: date},
+		{Version: "nightly", Date: date.Add(5 * time.Hour)},
+		{Version: "v1.10.0-rc.1", Date: date.Add(3 * time.Hour)},
+		{Version: "v1.10.0", Date: date.Add(2 * time.Hour)},
+		{Version: "hotfix", Date: date.Add(6 * time.Hour)},
+		{Version: "v1.9.1", Date: date.Add(4 * time.Hour), Prerelease: true},
+	}
+}
+
+func TestReleases_Sort(t *testing.T) {
+
+	releases := testReleases()
+	releases.Sort()
+
+	want := Versions{"v1.10.0", "v1.10.0-rc.1", "v1.9.1", "v1.9.0", "hotfix", "nightly"}
+	if got := releases.Versions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() got = %v, want %v", got, want)
+	}
+}
+
+func TestReleases_Latest(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		releases Releases
+		want     string
+	}{
+		{name: "last_published_not_prerelease", releases: testReleases(), want: "hotfix"},
+		{name: "prereleases_only", releases: Releases{
+			{Version: "v1.0.0-rc.1", Date: time.Unix(1, 0)},
+			{Version: "v1.0.0-rc.2", Date: time.Unix(2, 0)},
+		}, want: "v1.0.0-rc.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.releases.Latest(); got.Version != tt.want {
+				t.Errorf("Latest() got = %v, want %v", got.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleases_Labels(t *testing.T) {
+
+	releases := Releases{
+		{Version: "v1.10.0", Date: time.Unix(3, 0)},
+		{Version: "v1.10.0-rc.1", Date: time.Unix(4, 0)},
+		{Version: "v1.9.0", Date: time.Unix(2, 0)},
+		{Version: "nightly", Date: time.Unix(1, 0)},
+	}
+
+	want := []string{"v1.10.0 (latest)", "v1.10.0-rc.1 (prerelease)", "v1.9.0 (deployed)", "nightly"}
+	if got := releases.Labels("v1.9.0"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels() got = %v, want %v", got, want)
+	}
+}
+
+func TestReleases_Behind(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		version string
+		want    int
+	}{
+		{name: "latest", version: "hotfix", want: 0},
+		{name: "prereleases_not_counted", version: "v1.10.0", want: 2},
+		{name: "oldest", version: "v1.9.0", want: 3},
+		{name: "not_released", version: "v2.0.0", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testReleases().Behind(tt.version); got != tt.want {
+				t.Errorf("Behind() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gobeam/stringy"
+	"github.com/mitchellh/mapstructure"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ServiceRule maps the chart services it matches to their repo
+type ServiceRule struct {
+	// Name matches the chart service name exactly
+	Name string `json:"name,omitempty"`
+	// Match is a regex matched against the chart service name
+	Match string `json:"match,omitempty"`
+	// Ignore skips the matched services in changelogs and release lookups
+	Ignore bool `json:"ignore,omitempty"`
+
+	ServiceMapping `mapstructure:",squash"`
+
+	regex *regexp.Regexp
+}
+
+// ServiceRules are checked in order, the first matching rule wins
+type ServiceRules []*ServiceRule
+
+// ResolvedService is a chart service name resolved to its repo by the service rules
+type ResolvedService struct {
+	Name    string
+	Repo    string
+	Mapping ServiceMapping
+	Ignore  bool
+	// Rule is the matching rule and RuleIndex its position, Rule is nil when no rule matched
+	Rule      *ServiceRule
+	RuleIndex int
+}
+
+// Compile validates and compiles every rule, it must be called once the config is loaded. The error lists the
+// problems of every rule
+func (r ServiceRules) Compile() error {
+	problems := r.compile()
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(problems[0])
+	}
+	return fmt.Errorf("invalid service rules:\n %s", strings.Join(problems, "\n "))
+}
+
+// compile compiles every rule it can and returns the problems of all of them
+func (r ServiceRules) compile() []string {
+	var problems []string
+	for i, rule := range r {
+		if rule.Name == "" && rule.Match == "" {
+			problems = append(problems, fmt.Sprintf("service rule %d: name or match is required", i+1))
+		}
+		if !rule.Ignore && rule.Repo == "" {
+			problems = append(problems, fmt.Sprintf("service rule %d: repo is required", i+1))
+		}
+
+		if rule.Match != "" {
+			regex, err := regexp.Compile(rule.Match)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("service rule %d: invalid match %s", i+1, err))
+			}
+			rule.regex = regex
+		}
+
+		if err := rule.CompilePrefix(); err != nil {
+			problems = append(problems, fmt.Sprintf("service rule %d: %s", i+1, err))
+		}
+	}
+	return problems
+}
+
+// Matches checks the rule against the chart service name, an exact name takes precedence over the regex.
+// A regex rule never matches until the rules are compiled
+func (rule *ServiceRule) Matches(serviceName string) bool {
+	if rule.Name != "" {
+		return rule.Name == serviceName
+	}
+	return rule.regex != nil && rule.regex.MatchString(serviceName)
+}
+
+func (rule *ServiceRule) String() string {
+	if rule.Name != "" {
+		return fmt.Sprintf("name: %s", rule.Name)
+	}
+	return fmt.Sprintf("match: %s", rule.Match)
+}
+
+// Resolve returns the repo of a service, services without a matching rule use the kebab-cased name as repo
+func (r ServiceRules) Resolve(serviceName string) ResolvedService {
+	for i, rule := range r {
+		if rule.Matches(serviceName) {
+			return ResolvedService{
+				Name:      serviceName,
+				Repo:      strings.ToLower(rule.Repo),
+				Mapping:   rule.ServiceMapping,
+				Ignore:    rule.Ignore,
+				Rule:      rule,
+				RuleIndex: i,
+			}
+		}
+	}
+
+	return ResolvedService{
+		Name:      serviceName,
+		Repo:      strings.ToLower(stringy.New(serviceName).KebabCase().Get()),
+		RuleIndex: -1,
+	}
+}
+
+// Tag returns the repo tag of a service version
+func (rs ResolvedService) Tag(version string) string {
+	return rs.Mapping.Tag(rs.Name, version)
+}
+
+// Key identifies the changelog of the service, its repo or on multi tag repos its tag prefix without the
+// separator, e.g. "lender-graphql-api"
+func (rs ResolvedService) Key() string {
+	if !rs.Mapping.MultiTag {
+		return rs.Repo
+	}
+	return strings.TrimSuffix(rs.Tag(""), rs.Mapping.separator())
+}
+
+// Version returns the service version of a repo tag, it is false when the tag belongs to another service
+func (rs ResolvedService) Version(tag string) (string, bool) {
+	return rs.Mapping.Version(rs.Name, tag)
+}
+
+// ServiceRulesHook decodes the services config, it also accepts the legacy format where
+// each key is a regex, e.g. [{".*GraphqlApi.*": {"repo": "graphql-apis"}}]
+func ServiceRulesHook() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != reflect.TypeOf(ServiceRules{}) {
+			return data, nil
+		}
+
+		switch items := data.(type) {
+		case map[string]interface{}:
+			return legacyRules(items)
+		case []interface{}:
+			rules := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				rule, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, errors.New("service rules must be objects")
+				}
+				if isRule(rule) {
+					rules = append(rules, rule)
+					continue
+				}
+				legacy, err := legacyRules(rule)
+				if err != nil {
+					return nil, err
+				}
+				rules = append(rules, legacy...)
+			}
+			return rules, nil
+		}
+		return data, nil
+	}
+}
+
+func isRule(item map[string]interface{}) bool {
+	for key := range item {
+		switch strings.ToLower(key) {
+		case "name", "match", "repo", "ignore":
+			return true
+		}
+	}
+	return false
+}
+
+// legacyRules converts a map of regex to mapping into rules sorted by regex, as maps have no order.
+// Config keys are lowercased when loaded so legacy regexes are matched case insensitively
+func legacyRules(items map[string]interface{}) ([]interface{}, error) {
+	regexes := make([]string, 0, len(items))
+	for regex := range items {
+		regexes = append(regexes, regex)
+	}
+	sort.Strings(regexes)
+
+	rules := make([]interface{}, 0, len(items))
+	for _, regex := range regexes {
+		mapping, ok := items[regex].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid mapping of service %s", regex)
+		}
+
+		rule := make(map[string]interface{}, len(mapping)+1)
+		for k, v := range mapping {
+			rule[k] = v
+		}
+		rule["match"] = "(?i)" + regex
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"github.com/mitchellh/mapstructure"
+	"strings"
+	"testing"
+)
+
+func TestServiceRules_Resolve(t *testing.T) {
+
+	rules := ServiceRules{
+		{Name: "lenderGraphqlApi", ServiceMapping: ServiceMapping{Repo: "lender-api"}},
+		{Match: ".*GraphqlApi.*", ServiceMapping: ServiceMapping{Repo: "graphql-apis", MultiTag: true}},
+		{Match: ".*Api.*", ServiceMapping: ServiceMapping{Repo: "apis"}},
+		{Match: "^legacy", Ignore: true},
+	}
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		serviceName string
+		wantRepo    string
+		wantIndex   int
+		wantIgnore  bool
+		wantKey     string
+	}{
+		{name: "exact_name", serviceName: "lenderGraphqlApi", wantRepo: "lender-api", wantIndex: 0, wantKey: "lender-api"},
+		{name: "first_match_wins", serviceName: "applicantGraphqlApi", wantRepo: "graphql-apis", wantIndex: 1, wantKey: "applicant-graphql-api"},
+		{name: "later_match", serviceName: "applicationApi", wantRepo: "apis", wantIndex: 2, wantKey: "apis"},
+		{name: "ignored", serviceName: "legacyPortal", wantRepo: "", wantIndex: 3, wantIgnore: true, wantKey: ""},
+		{name: "default_repo", serviceName: "merchantPortal", wantRepo: "merchant-portal", wantIndex: -1, wantKey: "merchant-portal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules.Resolve(tt.serviceName)
+			if got.Repo != tt.wantRepo || got.RuleIndex != tt.wantIndex || got.Ignore != tt.wantIgnore {
+				t.Errorf("Resolve() got = %v %v %v, want %v %v %v", got.Repo, got.RuleIndex, got.Ignore, tt.wantRepo, tt.wantIndex, tt.wantIgnore)
+			}
+			if got.Key() != tt.wantKey {
+				t.Errorf("Key() got = %v, want %v", got.Key(), tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestServiceRules_Compile(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		rules        ServiceRules
+		wantErr      bool
+		wantProblems []string
+	}{
+		{name: "valid", rules: ServiceRules{{Match: ".*Api", ServiceMapping: ServiceMapping{Repo: "apis"}}}, wantErr: false},
+		{name: "missing_match", rules: ServiceRules{{ServiceMapping: ServiceMapping{Repo: "apis"}}}, wantErr: true},
+		{name: "missing_repo", rules: ServiceRules{{Match: ".*Api"}}, wantErr: true},
+		{name: "ignore_without_repo", rules: ServiceRules{{Match: ".*Api", Ignore: true}}, wantErr: false},
+		{name: "invalid_regex", rules: ServiceRules{{Match: "(", ServiceMapping: ServiceMapping{Repo: "apis"}}}, wantErr: true},
+		{name: "invalid_prefix", rules: ServiceRules{{Match: ".*Api", ServiceMapping: ServiceMapping{Repo: "apis", TagPrefix: "{{ .Name"}}}, wantErr: true},
+		{name: "every_invalid_rule", rules: ServiceRules{{Match: "("}, {Name: "api", ServiceMapping: ServiceMapping{Repo: "apis", TagPrefix: "{{ .Name"}}},
+			wantErr: true, wantProblems: []string{"service rule 1: repo is required", "service rule 1: invalid match", "service rule 2: "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Compile()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, problem := range tt.wantProblems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Compile() error = %v, want to contain %v", err, problem)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceRulesHook(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		input     interface{}
+		wantRepos []string
+	}{
+		{name: "rules", input: []interface{}{
+			map[string]interface{}{"match": ".*api", "repo": "apis"},
+			map[string]interface{}{"name": "lenderGraphqlApi", "repo": "graphql-apis", "multiTag": true},
+		}, wantRepos: []string{"apis", "graphql-apis"}},
+		{name: "legacy_list", input: []interface{}{
+			map[string]interface{}{
+				".*portal.*webpub$": map[string]interface{}{"repo": "portals-web-pub"},
+				".*graphqlapi.*":    map[string]interface{}{"repo": "graphql-apis", "multitag": true},
+			},
+		}, wantRepos: []string{"graphql-apis", "portals-web-pub"}},
+		{name: "legacy_map", input: map[string]interface{}{
+			".*graphqlapi.*": map[string]interface{}{"repo": "graphql-apis"},
+		}, wantRepos: []string{"graphql-apis"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules ServiceRules
+			decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{DecodeHook: ServiceRulesHook(), Result: &rules})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := decoder.Decode(tt.input); err != nil {
+				t.Fatal(err)
+			}
+			if err := rules.Compile(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rules) != len(tt.wantRepos) {
+				t.Fatalf("Decode() got = %v rules, want %v", len(rules), len(tt.wantRepos))
+			}
+			for i, rule := range rules {
+				if rule.Repo != tt.wantRepos[i] {
+					t.Errorf("Decode() got = %v, want %v", rule.Repo, tt.wantRepos[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version (https://semver.org), missing minor and patch numbers are 0
+type semver struct {
+	numbers    [3]int
+	prerelease []string
+}
+
+// parseSemver parses versions such as v1.2.3, 1.2.3-rc.1 or v1.2, build metadata is ignored
+func parseSemver(version string) (semver, bool) {
+	var v semver
+
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		v.prerelease = strings.Split(version[i+1:], ".")
+		version = version[:i]
+		for _, identifier := range v.prerelease {
+			if identifier == "" {
+				return v, false
+			}
+		}
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) > len(v.numbers) {
+		return v, false
+	}
+	for i, part := range parts {
+		if !isNumeric(part) {
+			return v, false
+		}
+		v.numbers[i], _ = strconv.Atoi(part)
+	}
+	return v, true
+}
+
+func (v semver) compare(other semver) int {
+	for i := range v.numbers {
+		if v.numbers[i] != other.numbers[i] {
+			return compareInts(v.numbers[i], other.numbers[i])
+		}
+	}
+
+	// a prerelease has a lower precedence than its release
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		a, b := v.prerelease[i], other.prerelease[i]
+		if a == b {
+			continue
+		}
+		aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+		switch {
+		case aNumeric && bNumeric:
+			x, _ := strconv.Atoi(a)
+			y, _ := strconv.Atoi(b)
+			return compareInts(x, y)
+		case aNumeric:
+			return -1
+		case bNumeric:
+			return 1
+		}
+		return strings.Compare(a, b)
+	}
+	return compareInts(len(v.prerelease), len(other.prerelease))
+}
+
+// CompareVersions compares two versions by semver precedence, it returns -1, 0 or 1. Any semver is greater
+// than a version that is not semver, two versions that are not semver are equal
+func CompareVersions(version1, version2 string) int {
+	v1, ok1 := parseSemver(version1)
+	v2, ok2 := parseSemver(version2)
+	switch {
+	case ok1 && ok2:
+		return v1.compare(v2)
+	case ok1:
+		return 1
+	case ok2:
+		return -1
+	}
+	return 0
+}
+
+// IsPrerelease reports whether the version is a semver prerelease, e.g. v1.2.0-rc.1
+func IsPrerelease(version string) bool {
+	v, ok := parseSemver(version)
+	return ok && len(v.prerelease) > 0
+}
+
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
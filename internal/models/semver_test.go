@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		version1 string
+		version2 string
+		want     int
+	}{
+		{name: "equal", version1: "v1.2.3", version2: "1.2.3", want: 0},
+		{name: "numeric_not_lexical", version1: "v1.10.0", version2: "v1.9.0", want: 1},
+		{name: "patch", version1: "v1.2.3", version2: "v1.2.4", want: -1},
+		{name: "missing_patch", version1: "v1.2", version2: "v1.2.0", want: 0},
+		{name: "prerelease_before_release", version1: "v1.2.0-rc.1", version2: "v1.2.0", want: -1},
+		{name: "prerelease_numeric", version1: "v1.2.0-rc.10", version2: "v1.2.0-rc.2", want: 1},
+		{name: "prerelease_numeric_before_alpha", version1: "v1.2.0-1", version2: "v1.2.0-alpha", want: -1},
+		{name: "prerelease_alpha", version1: "v1.2.0-alpha", version2: "v1.2.0-beta", want: -1},
+		{name: "prerelease_longer", version1: "v1.2.0-alpha.1", version2: "v1.2.0-alpha", want: 1},
+		{name: "build_ignored", version1: "v1.2.0+build.5", version2: "v1.2.0", want: 0},
+		{name: "semver_before_other", version1: "v0.0.1", version2: "latest", want: 1},
+		{name: "other_after_semver", version1: "release-2022", version2: "v1.0.0", want: -1},
+		{name: "others_equal", version1: "latest", version2: "stable", want: 0},
+		{name: "invalid_prerelease", version1: "v1.2.0-", version2: "v0.1.0", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareVersions(tt.version1, tt.version2); got != tt.want {
+				t.Errorf("CompareVersions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "v1.2.0", want: false},
+		{version: "v1.2.0-rc.1", want: true},
+		{version: "v1.2.0+build", want: false},
+		{version: "nightly-2022", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := IsPrerelease(tt.version); got != tt.want {
+				t.Errorf("IsPrerelease() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,34 @@
 package models
 
+import "sort"
+
 type Services map[string]*Service
 
+// ToArray returns the services sorted by name
 func (services Services) ToArray() []*Service {
 	arr := make([]*Service, 0, len(services))
 	for _, ser := range services {
 		arr = append(arr, ser)
 	}
+	sort.Slice(arr, func(i, j int) bool {
+		if arr[i].Name != arr[j].Name {
+			return arr[i].Name < arr[j].Name
+		}
+		return arr[i].HLMName < arr[j].HLMName
+	})
 	return arr
 }
 
+// Names returns the keys of the services sorted
+func (services Services) Names() []string {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 type Service struct {
 	Release
 	HLMName string
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TicketRef points to the change that referenced a ticket
+type TicketRef struct {
+	Repo        string
+	PullRequest int
+	SHA         string
+	URL         string
+}
+
+type Ticket struct {
+	Key  string
+	URL  string
+	Refs []TicketRef
+}
+
+type Tickets []*Ticket
+
+// Tickets scans every change for issue keys of the configured jira projects, each
+// ticket is listed once no matter how many services or changes reference it
+func (c Changelogs) Tickets(cfg JiraConfig) Tickets {
+	if len(cfg.Projects) == 0 {
+		return nil
+	}
+
+	projects := make([]string, 0, len(cfg.Projects))
+	for _, project := range cfg.Projects {
+		projects = append(projects, regexp.QuoteMeta(project))
+	}
+	// branch names are usually lowercase, e.g. fix/ing-123-description
+	keyRegex := regexp.MustCompile(fmt.Sprintf(`(?i)\b(?:%s)-\d+\b`, strings.Join(projects, "|")))
+
+	byKey := make(map[string]*Ticket)
+	var tickets Tickets
+	for _, repo := range c.Repos() {
+		changelog := c[repo]
+		for _, change := range changelog.Changes {
+			ref := TicketRef{
+				Repo:        repo,
+				PullRequest: change.PullRequest,
+				SHA:         change.SHA,
+				URL:         changelog.PullRequestURL(change),
+			}
+
+			for _, match := range keyRegex.FindAllString(change.Description+"\n"+change.Message, -1) {
+				key := strings.ToUpper(match)
+				ticket, ok := byKey[key]
+				if !ok {
+					ticket = &Ticket{Key: key, URL: cfg.TicketURL(key)}
+					byKey[key] = ticket
+					tickets = append(tickets, ticket)
+				}
+				if !ticket.hasRef(ref) {
+					ticket.Refs = append(ticket.Refs, ref)
+				}
+			}
+		}
+	}
+
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].Key < tickets[j].Key })
+	return tickets
+}
+
+func (cfg JiraConfig) TicketURL(key string) string {
+	if cfg.URL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(cfg.URL, "/"), key)
+}
+
+func (t *Ticket) hasRef(ref TicketRef) bool {
+	for _, r := range t.Refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func (r TicketRef) String() string {
+	var builder strings.Builder
+	builder.WriteString(r.Repo)
+	switch {
+	case r.PullRequest != 0:
+		fmt.Fprintf(&builder, " #%d", r.PullRequest)
+	case len(r.SHA) >= 7:
+		fmt.Fprintf(&builder, " %s", r.SHA[:7])
+	}
+	if r.URL != "" {
+		fmt.Fprintf(&builder, " %s", r.URL)
+	}
+	return builder.String()
+}
+
+func (t Tickets) String() string {
+	var builder strings.Builder
+	builder.WriteString("Tickets in this release:\n")
+	for _, ticket := range t {
+		fmt.Fprintf(&builder, "- %s", ticket.Key)
+		if ticket.URL != "" {
+			fmt.Fprintf(&builder, " %s", ticket.URL)
+		}
+		builder.WriteString("\n")
+		for _, ref := range ticket.Refs {
+			fmt.Fprintf(&builder, "    %s\n", ref)
+		}
+	}
+	return builder.String()
+}
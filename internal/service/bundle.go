@@ -0,0 +1,281 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+
+	_manifestFileName = "manifest.json"
+)
+
+// ExportOptions sets how a release is exported, an empty Archive exports a folder
+type ExportOptions struct {
+	Format  string
+	Archive string
+}
+
+// newManifest describes the exported release, its files must not include the manifest itself. The sources are
+// the helm chart refs and the ref range of each changelog
+func (s Service) newManifest(ctx context.Context, diff *models.Comparer, changelogs models.Changelogs, files []ExportFile) (*models.Manifest, error) {
+	manifest := &models.Manifest{
+		Platform:  diff.Platform,
+		Repo:      diff.Repo,
+		From:      diff.InitialVersion,
+		To:        diff.FinalVersion,
+		Exporter:  s.exporter(ctx),
+		Timestamp: time.Now().UTC(),
+		Files:     make(map[string]string, len(files)),
+	}
+
+	for name, updated := range diff.Changed {
+		manifest.Services = append(manifest.Services, models.ManifestService{Name: name, Change: models.ServiceChanged,
+			From: updated.Service.Version, To: updated.NewVersion})
+	}
+	for name, service := range diff.Insert {
+		manifest.Services = append(manifest.Services, models.ManifestService{Name: name, Change: models.ServiceAdded, To: service.Version})
+	}
+	for name, service := range diff.Deleted {
+		manifest.Services = append(manifest.Services, models.ManifestService{Name: name, Change: models.ServiceRemoved, From: service.Version})
+	}
+	sort.Slice(manifest.Services, func(i, j int) bool { return manifest.Services[i].Name < manifest.Services[j].Name })
+
+	var sources []models.ManifestSource
+	if diff.Repo != "" {
+		sources = append(sources, models.ManifestSource{Repo: diff.Repo, Ref: diff.InitialVersion},
+			models.ManifestSource{Repo: diff.Repo, Ref: diff.FinalVersion})
+	}
+	for _, key := range changelogs.Repos() {
+		changelog := changelogs[key]
+		for _, ref := range []string{changelog.From, changelog.To} {
+			if ref != "" {
+				sources = append(sources, models.ManifestSource{Repo: changelog.Repo, Ref: ref})
+			}
+		}
+	}
+
+	seen := make(map[models.ManifestSource]bool, len(sources))
+	for _, source := range sources {
+		if seen[source] {
+			continue
+		}
+		seen[source] = true
+
+		sha, err := s.gh.GetCommitSHA(ctx, s.config.Github.Org, source.Repo, source.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s sha of %s %w", source.Ref, source.Repo, err)
+		}
+		source.SHA = sha
+		manifest.Sources = append(manifest.Sources, source)
+	}
+
+	for _, file := range files {
+		manifest.Files[file.Name] = checksum(file.Content)
+	}
+
+	return manifest, nil
+}
+
+// exporter returns the GitHub login of the token owner, falling back to the os user
+func (s Service) exporter(ctx context.Context) string {
+	if ghUser, err := s.gh.GetAuthenticatedUser(ctx); err == nil && ghUser.GetLogin() != "" {
+		return ghUser.GetLogin()
+	}
+	if osUser, err := user.Current(); err == nil {
+		return osUser.Username
+	}
+	return "unknown"
+}
+
+// writeArchive writes the files inside the root folder of a tar.gz or zip archive
+func writeArchive(archivePath, archiveType, root string, files []ExportFile) error {
+	f, err := os.OpenFile(archivePath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	switch archiveType {
+	case ArchiveTarGz:
+		err = writeTarGz(f, root, files)
+	case ArchiveZip:
+		err = writeZip(f, root, files)
+	default:
+		err = fmt.Errorf("unknown archive type %s", archiveType)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writeTarGz(w io.Writer, root string, files []ExportFile) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    path.Join(root, file.Name),
+			Mode:    0644,
+			Size:    int64(len(file.Content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeZip(w io.Writer, root string, files []ExportFile) error {
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.Create(path.Join(root, file.Name))
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(file.Content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// VerifyArchive checks every file of an exported release archive against the checksums of its manifest
+func VerifyArchive(archivePath string) (*models.Manifest, error) {
+	files, err := readArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := files[_manifestFileName]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in archive", _manifestFileName)
+	}
+	delete(files, _manifestFileName)
+
+	var manifest models.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("reading manifest %w", err)
+	}
+
+	var problems []string
+	for name, sum := range manifest.Files {
+		content, ok := files[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is missing", name))
+		case checksum(content) != sum:
+			problems = append(problems, fmt.Sprintf("%s checksum does not match", name))
+		}
+		delete(files, name)
+	}
+	for name := range files {
+		problems = append(problems, fmt.Sprintf("%s is not in the manifest", name))
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return &manifest, fmt.Errorf("archive verification failed:\n %s", strings.Join(problems, "\n "))
+	}
+	return &manifest, nil
+}
+
+// readArchive returns the content of every file in the archive keyed by its name, without the root folder
+func readArchive(archivePath string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	if strings.HasSuffix(archivePath, "."+ArchiveZip) {
+		zr, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			files[trimRoot(zf.Name)] = content
+		}
+		return files, nil
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tr); err != nil {
+			return nil, err
+		}
+		files[trimRoot(header.Name)] = buf.Bytes()
+	}
+	return files, nil
+}
+
+func trimRoot(name string) string {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
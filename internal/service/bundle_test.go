@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/adam-putland/divido-cli/internal/models"
+	util "github.com/adam-putland/divido-cli/internal/util/github"
+	"github.com/adam-putland/divido-cli/internal/util/github/mock"
+	"github.com/google/go-github/v45/github"
+	"github.com/gorilla/mux"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVerifyArchive(t *testing.T) {
+
+	files := []ExportFile{
+		{Name: "JIRA_TICKET_TEXT.txt", Content: []byte("Base Helm Chart Change: v1.0.0 -> v1.1.0\n")},
+		{Name: "api_changelog.txt", Content: []byte("Features\n- add lenders (#3)\n")},
+	}
+
+	manifest := models.Manifest{Platform: "ing", From: "v1.0.0", To: "v1.1.0", Files: map[string]string{}}
+	for _, file := range files {
+		manifest.Files[file.Name] = checksum(file.Content)
+	}
+
+	tampered := manifest
+	tampered.Files = map[string]string{
+		"JIRA_TICKET_TEXT.txt": manifest.Files["JIRA_TICKET_TEXT.txt"],
+		"api_changelog.txt":    checksum([]byte("something else")),
+	}
+
+	tests := []struct {
+		name     string
+		archive  string
+		manifest models.Manifest
+		wantErr  bool
+	}{
+		{name: "tar_gz", archive: ArchiveTarGz, manifest: manifest, wantErr: false},
+		{name: "zip", archive: ArchiveZip, manifest: manifest, wantErr: false},
+		{name: "tampered_tar_gz", archive: ArchiveTarGz, manifest: tampered, wantErr: true},
+		{name: "tampered_zip", archive: ArchiveZip, manifest: tampered, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			content, err := json.Marshal(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			archivePath := filepath.Join(t.TempDir(), "release."+tt.archive)
+			err = writeArchive(archivePath, tt.archive, "release", append(files, ExportFile{Name: _manifestFileName, Content: content}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := VerifyArchive(archivePath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got == nil || got.Platform != "ing" {
+				t.Errorf("VerifyArchive() got = %v, want manifest of platform ing", got)
+			}
+		})
+	}
+}
+
+func TestService_NewManifestSources(t *testing.T) {
+
+	s := Service{
+		gh: &util.GithubClient{
+			Client: github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposCommitsByOwnerByRepoByRef,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.Write([]byte("sha-" + mux.Vars(r)["repo"] + "-" + mux.Vars(r)["ref"]))
+					}),
+				))),
+		},
+		config: &models.Config{Github: models.GithubConfig{Org: "test"}},
+	}
+
+	diff := &models.Comparer{Platform: "ing", Repo: "ing-platform-hlm", InitialVersion: "v1.0.0", FinalVersion: "v1.1.0"}
+	changelogs := models.Changelogs{
+		"application-api":    {Repo: "application-api", From: "v1.0.0", To: "v1.2.0"},
+		"lender-graphql-api": {Repo: "graphql-apis", From: "lender-graphql-api-v1.0.0", To: "lender-graphql-api-v1.1.0"},
+		"portal":             {Repo: "portal", To: "v2.0.0"},
+	}
+
+	manifest, err := s.newManifest(context.Background(), diff, changelogs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.ManifestSource{
+		{Repo: "ing-platform-hlm", Ref: "v1.0.0", SHA: "sha-ing-platform-hlm-v1.0.0"},
+		{Repo: "ing-platform-hlm", Ref: "v1.1.0", SHA: "sha-ing-platform-hlm-v1.1.0"},
+		{Repo: "application-api", Ref: "v1.0.0", SHA: "sha-application-api-v1.0.0"},
+		{Repo: "application-api", Ref: "v1.2.0", SHA: "sha-application-api-v1.2.0"},
+		{Repo: "graphql-apis", Ref: "lender-graphql-api-v1.0.0", SHA: "sha-graphql-apis-lender-graphql-api-v1.0.0"},
+		{Repo: "graphql-apis", Ref: "lender-graphql-api-v1.1.0", SHA: "sha-graphql-apis-lender-graphql-api-v1.1.0"},
+		{Repo: "portal", Ref: "v2.0.0", SHA: "sha-portal-v2.0.0"},
+	}
+	if !reflect.DeepEqual(manifest.Sources, want) {
+		t.Errorf("newManifest() got sources = %v, want %v", manifest.Sources, want)
+	}
+}
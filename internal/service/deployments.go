@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// _deployHistoryDepth is the number of commits of the env version files looked back to find when a version was deployed
+const _deployHistoryDepth = 10
+
+// deploymentTask loads the services of the latest chart of a platform, or of an env when envIndex is not -1
+type deploymentTask struct {
+	platIndex int
+	envIndex  int
+}
+
+// GetServiceDeployments returns the version of a service in the latest chart of each platform and in each env,
+// by its chart name or its repo name (matching every service of the repo). An env that cannot be loaded has its
+// error in its deployment
+func (s *Service) GetServiceDeployments(ctx context.Context, serviceName string) (models.Deployments, error) {
+	var tasks []deploymentTask
+	for platIndex, plat := range s.config.Platforms {
+		tasks = append(tasks, deploymentTask{platIndex: platIndex, envIndex: -1})
+		for envIndex := range plat.Envs {
+			tasks = append(tasks, deploymentTask{platIndex: platIndex, envIndex: envIndex})
+		}
+	}
+
+	found := make([]models.Deployments, len(tasks))
+	errs := s.Pool("Loading platforms and environments").Run(ctx, len(tasks), func(ctx context.Context, index int) error {
+		var err error
+		found[index], err = s.getTaskDeployments(ctx, tasks[index], serviceName)
+		return err
+	})
+
+	var deployments models.Deployments
+	deployed := 0
+	for index, task := range tasks {
+		if errs[index] == nil {
+			deployments = append(deployments, found[index]...)
+			deployed += len(found[index])
+			continue
+		}
+
+		failed := &models.Deployment{Platform: s.config.Platforms[task.platIndex].Name, Service: serviceName, Err: errs[index]}
+		if task.envIndex != -1 {
+			failed.Env = s.config.Platforms[task.platIndex].Envs[task.envIndex].Name
+		}
+		deployments = append(deployments, failed)
+	}
+
+	if deployed == 0 {
+		if err := errs.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s is not deployed in any platform", serviceName)
+	}
+
+	s.markBehind(ctx, deployments)
+	deployments.MarkFirst()
+	return deployments, nil
+}
+
+// getTaskDeployments returns the services matching the name in the chart or the env of the task
+func (s *Service) getTaskDeployments(ctx context.Context, task deploymentTask, serviceName string) (models.Deployments, error) {
+	plat := s.config.GetPlatform(task.platIndex)
+
+	if task.envIndex == -1 {
+		chart, err := s.GetPlat(ctx, plat.HelmChartRepo)
+		if err != nil {
+			return nil, err
+		}
+
+		var deployments models.Deployments
+		for _, name := range s.matchServices(chart.Services, serviceName) {
+			deployments = append(deployments, &models.Deployment{Platform: plat.Name, Service: name,
+				Version: chart.Services[name].Version})
+		}
+		return deployments, nil
+	}
+
+	env, err := s.GetEnv(ctx, task.platIndex, task.envIndex)
+	if err != nil {
+		return nil, err
+	}
+	// the task already runs in a pool, the env files are fetched inline
+	if err := s.loadEnvServices(ctx, env, plat, _inline); err != nil {
+		return nil, err
+	}
+
+	deployed := env.Deployed()
+	var deployments models.Deployments
+	for _, name := range s.matchServices(deployed, serviceName) {
+		_, override := env.Overrides[name]
+		deployments = append(deployments, &models.Deployment{Platform: plat.Name, Env: env.Name, Service: name,
+			Version: deployed[name].Version, Override: override})
+	}
+
+	s.markSince(ctx, env, plat, deployments)
+	return deployments, nil
+}
+
+// envCommit is a commit changing one of the files the services of an env are loaded from
+type envCommit struct {
+	sha  string
+	date time.Time
+}
+
+// envHistory returns the latest commits changing the version files of the env, newest first. It is complete when
+// it holds every commit changing them
+func (s *Service) envHistory(ctx context.Context, env *models.Environment) ([]envCommit, bool, error) {
+	var paths []string
+	if !env.OnlyOverrides {
+		paths = append(paths, _defaultChartVersionFilePath)
+	}
+	if env.ChartPath != "" {
+		paths = append(paths, env.ChartPath)
+	}
+	paths = append(paths, _defaultHelmOverridesFilePath)
+
+	var commits []envCommit
+	var cutoff time.Time
+	complete := true
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		found, err := s.gh.GetFileCommits(ctx, s.config.Github.Org, env.Repo, path, s.config.Github.MainBranch, _deployHistoryDepth)
+		if err != nil {
+			return nil, false, fmt.Errorf("getting the history of %s %w", path, err)
+		}
+		// older commits of a file whose history is cut may be missing, the others are not looked at past it
+		if len(found) == _deployHistoryDepth {
+			complete = false
+			if oldest := found[len(found)-1].GetCommit().GetCommitter().GetDate(); oldest.After(cutoff) {
+				cutoff = oldest
+			}
+		}
+		for _, commit := range found {
+			if !seen[commit.GetSHA()] {
+				seen[commit.GetSHA()] = true
+				commits = append(commits, envCommit{sha: commit.GetSHA(), date: commit.GetCommit().GetCommitter().GetDate()})
+			}
+		}
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].date.After(commits[j].date)
+	})
+	for len(commits) > 0 && commits[len(commits)-1].date.Before(cutoff) {
+		commits = commits[:len(commits)-1]
+	}
+	if len(commits) > _deployHistoryDepth {
+		commits, complete = commits[:_deployHistoryDepth], false
+	}
+	return commits, complete, nil
+}
+
+// markSince sets when the env got the version of each deployment, going back through the commits changing its
+// version files until the version differs. Since is left unknown when the history cannot be fetched, a version
+// still running at the oldest commit looked at is marked SinceOlder unless that commit created the files
+func (s *Service) markSince(ctx context.Context, env *models.Environment, plat *models.PlatformConfig, deployments models.Deployments) {
+	commits, complete, err := s.envHistory(ctx, env)
+	if err != nil || len(commits) == 0 {
+		return
+	}
+
+	// the newest commit has the files of the main branch
+	for _, d := range deployments {
+		d.Since = commits[0].date
+	}
+
+	pending := deployments
+	for _, commit := range commits[1:] {
+		deployed, err := s.envServicesAt(ctx, env, plat, commit.sha)
+		if err != nil {
+			complete = false
+			break
+		}
+
+		var running models.Deployments
+		for _, d := range pending {
+			if service, ok := deployed[d.Service]; ok && service.Version == d.Version {
+				d.Since = commit.date
+				running = append(running, d)
+			}
+		}
+		if pending = running; len(pending) == 0 {
+			return
+		}
+	}
+
+	for _, d := range pending {
+		d.SinceOlder = !complete
+	}
+}
+
+// envServicesAt returns the services deployed in the env at a commit of its repo, fetched inline
+func (s *Service) envServicesAt(ctx context.Context, env *models.Environment, plat *models.PlatformConfig, sha string) (models.Services, error) {
+	at := &models.Environment{EnvironmentConfig: env.EnvironmentConfig}
+	if !at.OnlyOverrides {
+		version, err := s.gh.GetContent(ctx, s.config.Github.Org, env.Repo, _defaultChartVersionFilePath, sha)
+		if err != nil {
+			return nil, err
+		}
+		at.HelmChartVersion = string(version)
+	}
+
+	if err := s.loadEnvServicesAt(ctx, at, plat, sha, _inline); err != nil {
+		return nil, err
+	}
+	return at.Deployed(), nil
+}
+
+// matchServices returns the names of the services named serviceName or in the serviceName repo, sorted
+func (s *Service) matchServices(services models.Services, serviceName string) []string {
+	if _, ok := services[serviceName]; ok {
+		return []string{serviceName}
+	}
+
+	var names []string
+	for _, name := range services.Names() {
+		if resolved := s.ResolveService(name); !resolved.Ignore && resolved.Repo == serviceName {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// markBehind sets how many releases behind the latest one each deployment is, the releases of each service are
+// fetched once. The deployments of a service whose releases cannot be fetched get the error as BehindErr
+func (s *Service) markBehind(ctx context.Context, deployments models.Deployments) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, d := range deployments {
+		if d.Err == nil && !seen[d.Service] {
+			seen[d.Service] = true
+			names = append(names, d.Service)
+		}
+	}
+
+	var mu sync.Mutex
+	releases := make(map[string]models.Releases, len(names))
+	errs := make(map[string]error)
+	s.Pool("Obtaining versions").Run(ctx, len(names), func(ctx context.Context, index int) error {
+		serviceReleases, err := s.GetServiceReleases(ctx, names[index])
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[names[index]] = fmt.Errorf("%s %w", names[index], err)
+			return err
+		}
+		releases[names[index]] = serviceReleases
+		return nil
+	})
+
+	for _, d := range deployments {
+		if d.Err != nil {
+			continue
+		}
+		if err := errs[d.Service]; err != nil {
+			d.BehindErr = err
+			continue
+		}
+		d.Behind = releases[d.Service].Behind(d.Version)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"github.com/adam-putland/divido-cli/internal/models"
+	util "github.com/adam-putland/divido-cli/internal/util/github"
+	"github.com/adam-putland/divido-cli/internal/util/github/mock"
+	"github.com/google/go-github/v45/github"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestService_GetServiceDeployments(t *testing.T) {
+
+	config := models.Config{
+		Github: models.GithubConfig{Org: "test", MainBranch: "master"},
+		Platforms: []models.PlatformConfig{{Name: "ing", HelmChartRepo: "ing-platform-hlm", Envs: []models.EnvironmentConfig{
+			{Name: "test", Repo: "ing-test-inf"},
+			{Name: "sandbox", Repo: "ing-sandbox-inf", ChartPath: "configs/versions.yaml", OnlyOverrides: true},
+			{Name: "prod", Repo: "ing-prod-inf"},
+			{Name: "missing", Repo: "ing-missing-inf"},
+		}}},
+		Services: models.ServiceRules{{Name: "applicationApi", ServiceMapping: models.ServiceMapping{Repo: "application-api"}}},
+	}
+
+	files := map[string]string{
+		"/repos/test/ing-platform-hlm/contents/charts/services/values.yaml@v1.2.0":     "services:\n  applicationApi:\n    serviceVersion: v1.1.0\n",
+		"/repos/test/ing-platform-hlm/contents/charts/services/values.yaml@v1.1.0":     "services:\n  applicationApi:\n    serviceVersion: v1.0.0\n",
+		"/repos/test/ing-test-inf/contents/helm/platform/CURRENT_CHART_VERSION@master": "1.2.0\n",
+		"/repos/test/ing-prod-inf/contents/helm/platform/CURRENT_CHART_VERSION@master": "1.1.0\n",
+		"/repos/test/ing-sandbox-inf/contents/configs/versions.yaml@master":            "services:\n  applicationApi:\n    serviceVersion: v1.1.0\n",
+		"/repos/test/ing-test-inf/contents/helm/platform/CURRENT_CHART_VERSION@t1":     "1.1.0\n",
+		"/repos/test/ing-sandbox-inf/contents/configs/versions.yaml@s1":                "services:\n  applicationApi:\n    serviceVersion: v1.1.0\n",
+	}
+	date := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	// sandbox got v1.1.0 before test, which ran v1.0.0 until its chart was bumped
+	commit := func(sha string, date time.Time) *github.RepositoryCommit {
+		return &github.RepositoryCommit{SHA: github.String(sha), Commit: &github.Commit{Committer: &github.CommitAuthor{Date: &date}}}
+	}
+	history := map[string][]*github.RepositoryCommit{
+		"/repos/test/ing-test-inf/commits?helm/platform/CURRENT_CHART_VERSION": {commit("t2", date.Add(3*day)), commit("t1", date.Add(day))},
+		"/repos/test/ing-sandbox-inf/commits?configs/versions.yaml":            {commit("s2", date.Add(2*day)), commit("s1", date)},
+		"/repos/test/ing-prod-inf/commits?helm/platform/CURRENT_CHART_VERSION": {commit("p1", date.Add(4*day))},
+	}
+
+	s := Service{
+		gh: &util.GithubClient{
+			Client: github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposReleasesLatestByOwnerByRepo, github.RepositoryRelease{TagName: github.String("v1.2.0")}),
+				mock.WithRequestMatch(mock.GetReposReleasesByOwnerByRepo, []github.RepositoryRelease{
+					{TagName: github.String("v1.2.0"), PublishedAt: &github.Timestamp{Time: date.Add(2 * time.Hour)}},
+					{TagName: github.String("v1.1.0"), PublishedAt: &github.Timestamp{Time: date.Add(time.Hour)}},
+					{TagName: github.String("v1.0.0"), PublishedAt: &github.Timestamp{Time: date}},
+				}),
+				mock.WithRequestMatchHandler(
+					mock.GetReposContentsByOwnerByRepoByPath,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						content, ok := files[r.URL.Path+"@"+r.URL.Query().Get("ref")]
+						if !ok {
+							mock.WriteError(w, http.StatusNotFound, "not found")
+							return
+						}
+						w.Write(mock.MustMarshal(github.RepositoryContent{Type: github.String("file"), Content: github.String(content)}))
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposCommitsByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						commits := history[r.URL.Path+"?"+r.URL.Query().Get("path")]
+						if commits == nil {
+							commits = []*github.RepositoryCommit{}
+						}
+						w.Write(mock.MustMarshal(commits))
+					}),
+				))),
+		},
+		config: &config,
+	}
+
+	deployments, err := s.GetServiceDeployments(context.Background(), "application-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type row struct {
+		Env      string
+		Version  string
+		Override bool
+		Behind   int
+		Since    time.Time
+		First    bool
+		Failed   bool
+		Unknown  bool
+	}
+	var got []row
+	for _, d := range deployments {
+		got = append(got, row{Env: d.Env, Version: d.Version, Override: d.Override, Behind: d.Behind, Since: d.Since, First: d.First,
+			Failed: d.Err != nil, Unknown: d.BehindErr != nil})
+	}
+
+	want := []row{
+		{Env: "", Version: "v1.1.0", Behind: 1},
+		{Env: "test", Version: "v1.1.0", Behind: 1, Since: date.Add(3 * day)},
+		{Env: "sandbox", Version: "v1.1.0", Override: true, Behind: 1, Since: date, First: true},
+		{Env: "prod", Version: "v1.0.0", Behind: 2, Since: date.Add(4 * day), First: true},
+		{Env: "missing", Failed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetServiceDeployments() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestService_GetServiceDeploymentsUnknownBehind(t *testing.T) {
+
+	config := models.Config{
+		Github: models.GithubConfig{Org: "test", MainBranch: "master"},
+		Platforms: []models.PlatformConfig{{Name: "ing", HelmChartRepo: "ing-platform-hlm", Envs: []models.EnvironmentConfig{
+			{Name: "test", Repo: "ing-test-inf"},
+		}}},
+	}
+
+	s := Service{
+		gh: &util.GithubClient{
+			Client: github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposReleasesLatestByOwnerByRepo, github.RepositoryRelease{TagName: github.String("v1.2.0")}),
+				mock.WithRequestMatchHandler(
+					mock.GetReposReleasesByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						mock.WriteError(w, http.StatusInternalServerError, "unavailable")
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposContentsByOwnerByRepoByPath,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						content := "1.2.0\n"
+						if r.URL.Path == "/repos/test/ing-platform-hlm/contents/charts/services/values.yaml" {
+							content = "services:\n  applicationApi:\n    serviceVersion: v1.1.0\n"
+						} else if r.URL.Path != "/repos/test/ing-test-inf/contents/helm/platform/CURRENT_CHART_VERSION" {
+							mock.WriteError(w, http.StatusNotFound, "not found")
+							return
+						}
+						w.Write(mock.MustMarshal(github.RepositoryContent{Type: github.String("file"), Content: github.String(content)}))
+					}),
+				))),
+		},
+		config: &config,
+	}
+
+	deployments, err := s.GetServiceDeployments(context.Background(), "applicationApi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 2 {
+		t.Fatalf("GetServiceDeployments() got = %d deployments, want 2", len(deployments))
+	}
+	for _, d := range deployments {
+		if d.Err != nil || d.BehindErr == nil || d.Version != "v1.1.0" {
+			t.Errorf("GetServiceDeployments() %s got = %s %v %v, want v1.1.0 with an unknown behind", d.Env, d.Version, d.Err, d.BehindErr)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+)
+
+// DiscoverPlatforms proposes the platforms of the org from its helm chart and environment repos
+func (s Service) DiscoverPlatforms(ctx context.Context) ([]models.PlatformConfig, error) {
+	repos, err := s.gh.ListOrgRepos(ctx, s.config.Github.Org)
+	if err != nil {
+		return nil, fmt.Errorf("listing repos of %s %w", s.config.Github.Org, err)
+	}
+
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if !repo.GetArchived() {
+			names = append(names, repo.GetName())
+		}
+	}
+	return models.DiscoverPlatforms(names), nil
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	FormatText       = "text"
+	FormatMarkdown   = "markdown"
+	FormatHTML       = "html"
+	FormatConfluence = "confluence"
+)
+
+var (
+	//go:embed templates/*.tmpl
+	_templates embed.FS
+
+	_defaultExportDir = `./releases/release-{{ .Comparer.InitialVersion }}-{{ .Comparer.FinalVersion }}-{{ .Date.Format "02-01-06" }}`
+
+	_exportFormats = map[string]models.ExportFormatConfig{
+		FormatText: {
+			Template:          "text.tmpl",
+			FileName:          "JIRA_TICKET_TEXT.txt",
+			ChangelogFileName: "{{ .Repo }}_changelog.txt",
+		},
+		FormatMarkdown: {
+			Template:          "markdown.tmpl",
+			FileName:          "RELEASE.md",
+			ChangelogFileName: "{{ .Repo }}_changelog.md",
+		},
+		FormatHTML: {
+			Template:          "html.tmpl",
+			FileName:          "release.html",
+			ChangelogFileName: "{{ .Repo }}_changelog.html",
+			HTML:              true,
+		},
+		FormatConfluence: {
+			Template:          "confluence.tmpl",
+			FileName:          "release.confluence.txt",
+			ChangelogFileName: "{{ .Repo }}_changelog.confluence.txt",
+		},
+	}
+)
+
+// ReleaseExport is the data given to the release templates
+type ReleaseExport struct {
+	Comparer   *models.Comparer
+	Changelogs models.Changelogs
+	Tickets    models.Tickets
+	Date       time.Time
+}
+
+// ChangelogExport is the data given to the changelog templates, one per service repo
+type ChangelogExport struct {
+	*ReleaseExport
+	Repo      string
+	Changelog *models.Changelog
+}
+
+type ExportFile struct {
+	Name    string
+	Content []byte
+}
+
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// ExportFormats returns the available formats, the configured default first
+func (s Service) ExportFormats() []string {
+	formats := make([]string, 0, len(_exportFormats)+len(s.config.Export.Formats))
+	for name := range _exportFormats {
+		formats = append(formats, name)
+	}
+	for name := range s.config.Export.Formats {
+		if _, ok := _exportFormats[name]; !ok {
+			formats = append(formats, name)
+		}
+	}
+
+	defaultFormat := s.defaultExportFormat()
+	sort.Slice(formats, func(i, j int) bool {
+		if formats[i] == defaultFormat || formats[j] == defaultFormat {
+			return formats[i] == defaultFormat
+		}
+		return formats[i] < formats[j]
+	})
+	return formats
+}
+
+// RenderRelease renders the release in the given format, returning the directory it
+// should be exported to and its files
+func (s Service) RenderRelease(ctx context.Context, diff *models.Comparer, format string) (string, []ExportFile, error) {
+	dir, files, _, err := s.renderRelease(ctx, diff, format)
+	return dir, files, err
+}
+
+// renderRelease renders the release like RenderRelease, also returning the changelogs it rendered
+func (s Service) renderRelease(ctx context.Context, diff *models.Comparer, format string) (string, []ExportFile, models.Changelogs, error) {
+
+	formatCfg, embedded, err := s.exportFormat(format)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	tpl, err := parseExportTemplate(formatCfg, embedded)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("parsing %s template %w", format, err)
+	}
+
+	changelogs, removedKeys, err := s.getChangelogsFromDiff(ctx, diff)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// the caller's diff keeps its color, the removed services link the release of their last version
+	rendered := *diff
+	rendered.DisableColor = true
+	rendered.Deleted = make(models.Services, len(diff.Deleted))
+	for name, service := range diff.Deleted {
+		deleted := *service
+		if key, ok := removedKeys[name]; ok {
+			deleted.URL = changelogs[key].URL
+		}
+		rendered.Deleted[name] = &deleted
+	}
+	data := &ReleaseExport{
+		Comparer:   &rendered,
+		Changelogs: changelogs,
+		Tickets:    changelogs.Tickets(s.config.Jira),
+		Date:       time.Now().UTC(),
+	}
+
+	exportDir := s.config.Export.Dir
+	if exportDir == "" {
+		exportDir = _defaultExportDir
+	}
+	dir, err := renderName(exportDir, data)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	fileName, err := renderName(formatCfg.FileName, data)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.ExecuteTemplate(&buf, "release", data); err != nil {
+		return "", nil, nil, fmt.Errorf("rendering release %w", err)
+	}
+	files := []ExportFile{{Name: fileName, Content: buf.Bytes()}}
+
+	for _, repo := range changelogs.Repos() {
+		changelogData := &ChangelogExport{
+			ReleaseExport: data,
+			Repo:          repo,
+			Changelog:     changelogs[repo],
+		}
+
+		fileName, err := renderName(formatCfg.ChangelogFileName, changelogData)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.ExecuteTemplate(&buf, "changelog", changelogData); err != nil {
+			return "", nil, nil, fmt.Errorf("rendering %s changelog %w", repo, err)
+		}
+		files = append(files, ExportFile{Name: fileName, Content: buf.Bytes()})
+	}
+
+	return dir, files, changelogs, nil
+}
+
+// ExportRelease renders the release and writes it to a folder or to an archive with a manifest,
+// returning the path it was exported to
+func (s Service) ExportRelease(ctx context.Context, diff *models.Comparer, opts ExportOptions) (string, error) {
+
+	dir, files, changelogs, err := s.renderRelease(ctx, diff, opts.Format)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Archive != "" {
+		manifest, err := s.newManifest(ctx, diff, changelogs, files)
+		if err != nil {
+			return "", err
+		}
+
+		content, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		files = append(files, ExportFile{Name: _manifestFileName, Content: content})
+
+		if err := os.MkdirAll(filepath.Dir(dir), os.ModePerm); err != nil {
+			return "", err
+		}
+
+		archivePath := fmt.Sprintf("%s.%s", dir, opts.Archive)
+		if err := writeArchive(archivePath, opts.Archive, filepath.Base(dir), files); err != nil {
+			return "", err
+		}
+		return archivePath, nil
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.Name), file.Content, 0644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// ArchiveOptions returns the archive types, the configured default first, an empty type exports a folder
+func (s Service) ArchiveOptions() []string {
+	archives := []string{"", ArchiveTarGz, ArchiveZip}
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i] == s.config.Export.Archive && archives[j] != s.config.Export.Archive
+	})
+	return archives
+}
+
+func (s Service) defaultExportFormat() string {
+	if s.config.Export.Format != "" {
+		return s.config.Export.Format
+	}
+	return FormatText
+}
+
+// exportFormat merges the configured format with the built-in one of the same name, it
+// also returns if the template is one of the embedded ones
+func (s Service) exportFormat(format string) (models.ExportFormatConfig, bool, error) {
+	builtin, isBuiltin := _exportFormats[format]
+	custom, isCustom := s.config.Export.Formats[format]
+	if !isBuiltin && !isCustom {
+		return builtin, false, fmt.Errorf("unknown export format %s", format)
+	}
+
+	if !isCustom {
+		return builtin, true, nil
+	}
+
+	embedded := false
+	if custom.Template == "" {
+		if !isBuiltin {
+			return custom, false, fmt.Errorf("export format %s has no template", format)
+		}
+		custom.Template = builtin.Template
+		custom.HTML = builtin.HTML
+		embedded = true
+	}
+	if custom.FileName == "" {
+		custom.FileName = builtin.FileName
+	}
+	if custom.ChangelogFileName == "" {
+		custom.ChangelogFileName = builtin.ChangelogFileName
+	}
+
+	if custom.FileName == "" || custom.ChangelogFileName == "" {
+		return custom, false, fmt.Errorf("export format %s requires fileName and changelogFileName", format)
+	}
+
+	return custom, embedded, nil
+}
+
+func parseExportTemplate(cfg models.ExportFormatConfig, embedded bool) (executor, error) {
+	var content []byte
+	var err error
+	if embedded {
+		content, err = _templates.ReadFile(path.Join("templates", cfg.Template))
+	} else {
+		content, err = os.ReadFile(cfg.Template)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.HTML {
+		return htmltemplate.New(filepath.Base(cfg.Template)).Parse(string(content))
+	}
+	return template.New(filepath.Base(cfg.Template)).Parse(string(content))
+}
+
+func renderName(text string, data interface{}) (string, error) {
+	tpl, err := template.New("").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"bytes"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportTemplates(t *testing.T) {
+
+	changelog := &models.Changelog{Org: "dividohq", Repo: "application-api", From: "v1.0.0", To: "v1.1.0", Changes: []*models.Change{
+		{Type: models.ChangeFeat, Scope: "api", Description: "add <lenders>", PullRequest: 3, Author: "jane"},
+		{Type: models.ChangeFix, Description: "handle ING-12", SHA: "0123456789"},
+	}}
+	changelogs := models.Changelogs{"application-api": changelog}
+
+	data := &ReleaseExport{
+		Comparer: &models.Comparer{
+			InitialVersion: "v1.31.0",
+			FinalVersion:   "v1.32.0",
+			Changed: map[string]*models.ServiceUpdated{"applicationApi": {
+				Service:    &models.Service{HLMName: "applicationApi", Release: models.Release{Version: "v1.0.0"}},
+				NewVersion: "v1.1.0",
+			}},
+			DisableColor: true,
+		},
+		Changelogs: changelogs,
+		Tickets:    changelogs.Tickets(models.JiraConfig{Projects: []string{"ING"}}),
+		Date:       time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		format    string
+		release   string
+		changelog string
+	}{
+		{format: FormatText, release: "applicationApi: v1.0.0 -> v1.1.0", changelog: "- api: add <lenders> (#3) @jane"},
+		{format: FormatMarkdown, release: "| applicationApi | v1.0.0 | v1.1.0 |", changelog: "([#3](https://github.com/dividohq/application-api/pull/3))"},
+		{format: FormatHTML, release: "<td>applicationApi</td>", changelog: "add &lt;lenders&gt;"},
+		{format: FormatConfluence, release: "|applicationApi|v1.0.0|v1.1.0|", changelog: "[commit|https://github.com/dividohq/application-api/commit/0123456789]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			tpl, err := parseExportTemplate(_exportFormats[tt.format], true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var release bytes.Buffer
+			if err := tpl.ExecuteTemplate(&release, "release", data); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(release.String(), tt.release) || !strings.Contains(release.String(), "ING-12") {
+				t.Errorf("release got = %s, want to contain %s", release.String(), tt.release)
+			}
+
+			var changelogOut bytes.Buffer
+			if err := tpl.ExecuteTemplate(&changelogOut, "changelog", &ChangelogExport{ReleaseExport: data, Repo: "application-api", Changelog: changelog}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(changelogOut.String(), tt.changelog) {
+				t.Errorf("changelog got = %s, want to contain %s", changelogOut.String(), tt.changelog)
+			}
+		})
+	}
+}
@@ -7,26 +7,22 @@ import (
 	"github.com/adam-putland/divido-cli/internal/models"
 	"github.com/adam-putland/divido-cli/internal/util"
 	"github.com/adam-putland/divido-cli/internal/util/github"
-	"github.com/gobeam/stringy"
-	"log"
-	"os"
-	"path/filepath"
-	"regexp"
+	gogithub "github.com/google/go-github/v45/github"
 	"strings"
-	"time"
+	"sync"
 )
 
 var (
 	_defaultChartVersionFilePath  = "helm/platform/CURRENT_CHART_VERSION"
 	_defaultHelmOverridesFilePath = "helm/platform/versions.yaml"
 	_defaultChatServicesFilePath  = "charts/services/values.yaml"
-	_defaultReleasesPath          = "./releases"
-	_defaultReleaseFileName       = "JIRA_TICKET_TEXT.txt"
 )
 
 type Service struct {
 	gh     *github.GithubClient
 	config *models.Config
+	// snapshots caches the services of the helm chart tags, nil disables it
+	snapshots *Snapshots
 }
 
 func New(
@@ -34,8 +30,9 @@ func New(
 	config *models.Config,
 ) *Service {
 	return &Service{
-		gh:     gh,
-		config: config,
+		gh:        gh,
+		config:    config,
+		snapshots: DefaultSnapshots(),
 	}
 }
 
@@ -43,22 +40,33 @@ func (s Service) GetConfig() *models.Config {
 	return s.config
 }
 
-func (s *Service) GetChangelog(ctx context.Context, name string, release1, release2 *models.Release) (string, error) {
+// _inline runs the tasks one after the other, for tasks already running in a pool
+var _inline = util.Pool{Concurrency: 1}
 
-	version1 := release1.Version
-	version2 := release2.Version
-
-	if release1.Date.After(release2.Date) {
-		version1 = release2.Version
-		version2 = release1.Version
+// Pool runs many GitHub requests at once, up to the configured concurrency, drawing their progress with the label.
+// An empty label draws nothing
+func (s Service) Pool(label string) util.Pool {
+	pool := util.Pool{Concurrency: s.config.Github.Concurrency}
+	if label != "" {
+		pool.Progress = util.NewProgress(label)
 	}
+	return pool
+}
 
-	resp, err := s.gh.GetChangelog(ctx, s.config.Github.Org, name, version1, version2)
-	if err != nil {
-		return "", err
+// GetChangelog returns the changelog between two releases of a service by its chart or repo name
+func (s *Service) GetChangelog(ctx context.Context, serviceName string, release1, release2 *models.Release) (*models.Changelog, error) {
+
+	repoName := s.ResolveService(serviceName).Repo
+
+	version1 := release1.Tag
+	version2 := release2.Tag
+
+	if release1.Date.After(release2.Date) {
+		version1 = release2.Tag
+		version2 = release1.Tag
 	}
 
-	return resp.Body, nil
+	return s.gh.GetChangelog(ctx, s.config.Github.Org, repoName, version1, version2)
 }
 
 func (s *Service) GetLatest(ctx context.Context, name string) (*models.Release, error) {
@@ -67,32 +75,68 @@ func (s *Service) GetLatest(ctx context.Context, name string) (*models.Release,
 		return nil, err
 	}
 
-	return &models.Release{
-		Name:      name,
-		Version:   *repo.TagName,
-		Changelog: *repo.Body,
-		URL:       *repo.HTMLURL,
-	}, nil
+	return newRelease(name, repo), nil
 }
 
+// GetRepoReleases returns the releases of a repo sorted by semver descending
 func (s *Service) GetRepoReleases(ctx context.Context, name string) (models.Releases, error) {
 	repository, err := s.gh.GetReleases(ctx, s.config.Github.Org, name)
 	if err != nil {
 		return nil, err
 	}
-	arr := make([]*models.Release, 0, len(repository))
+	return newReleases(name, repository), nil
+}
+
+// GetServiceReleases returns the releases of a service by its chart or repo name, on multi tag
+// repos only the service tags are kept and their versions have no tag prefix
+func (s *Service) GetServiceReleases(ctx context.Context, serviceName string) (models.Releases, error) {
+	resolved := s.ResolveService(serviceName)
+	if resolved.Ignore {
+		return nil, util.ErrIgnoredService
+	}
+
+	releases, err := s.GetRepoReleases(ctx, resolved.Repo)
+	if err != nil {
+		return nil, err
+	}
+	return serviceReleases(resolved, releases), nil
+}
 
-	for _, repo := range repository {
-		arr = append(arr, &models.Release{
-			Name:      name,
-			Version:   *repo.TagName,
-			Changelog: *repo.Body,
-			URL:       *repo.HTMLURL,
-			Date:      repo.PublishedAt.Time,
-		})
+// serviceReleases keeps the releases of the service on multi tag repos, their versions have no tag prefix
+func serviceReleases(resolved models.ResolvedService, releases models.Releases) models.Releases {
+	if !resolved.Mapping.MultiTag {
+		return releases
 	}
 
-	return arr, nil
+	filtered := make(models.Releases, 0, len(releases))
+	for _, release := range releases {
+		if version, ok := resolved.Version(release.Tag); ok {
+			release.Version = version
+			filtered = append(filtered, release)
+		}
+	}
+	filtered.Sort()
+	return filtered
+}
+
+// GetServiceLatest returns the latest release of a service by its chart or repo name, on multi tag
+// repos the repo latest release may belong to another service so the latest service tag is used
+func (s *Service) GetServiceLatest(ctx context.Context, serviceName string) (*models.Release, error) {
+	resolved := s.ResolveService(serviceName)
+	if !resolved.Mapping.MultiTag && !resolved.Ignore {
+		return s.GetLatest(ctx, resolved.Repo)
+	}
+
+	releases, err := s.GetServiceReleases(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := releases.Latest()
+	if latest == nil {
+		return nil, fmt.Errorf("no releases of %s found in %s", serviceName, resolved.Repo)
+	}
+	return latest, nil
 }
 
 func (s Service) GetEnv(ctx context.Context, platIndex, envIndex int) (*models.Environment, error) {
@@ -123,55 +167,87 @@ func (s Service) GetEnv(ctx context.Context, platIndex, envIndex int) (*models.E
 	return &env, nil
 }
 
+// envSource is a values file loaded into an environment, overrides replace the chart services
+type envSource struct {
+	file      github.FileRef
+	overrides bool
+	// optional sources are skipped when they cannot be fetched
+	optional bool
+}
+
 func (s *Service) LoadEnvServices(ctx context.Context, env *models.Environment, platIndex int) error {
 
 	plat := s.config.GetPlatform(platIndex)
 	if plat == nil {
 		return util.ErrMissingPlat
 	}
+	return s.loadEnvServices(ctx, env, plat, s.Pool("Loading environment"))
+}
 
-	if !env.OnlyOverrides {
-		content, err := s.gh.GetContent(ctx, s.config.Github.Org,
-			plat.HelmChartRepo, _defaultChatServicesFilePath, env.GetHCVersion())
-		if err != nil {
-			return err
-		}
+func (s *Service) loadEnvServices(ctx context.Context, env *models.Environment, plat *models.PlatformConfig, pool util.Pool) error {
+	return s.loadEnvServicesAt(ctx, env, plat, s.config.Github.MainBranch, pool)
+}
 
-		services, err := NewParser(content).Load()
-		if err != nil {
-			return err
-		}
+// loadEnvServicesAt loads the services of the env from its files at the ref, e.g. a commit of its history
+func (s *Service) loadEnvServicesAt(ctx context.Context, env *models.Environment, plat *models.PlatformConfig, ref string, pool util.Pool) error {
 
-		env.Services = services
+	// sources are applied in order, the helm overrides file replaces the chart path overrides
+	var sources []envSource
+	if !env.OnlyOverrides {
+		sources = append(sources, envSource{file: github.FileRef{Repo: plat.HelmChartRepo, Path: _defaultChatServicesFilePath, Ref: env.GetHCVersion()}})
 	}
-
 	// if no ChartPath will load services directly from the env repo
 	if env.ChartPath != "" {
-		content, err := s.gh.GetContent(ctx, s.config.Github.Org,
-			env.Repo, env.ChartPath, s.config.Github.MainBranch)
-		if err != nil {
-			return err
-		}
+		sources = append(sources, envSource{file: github.FileRef{Repo: env.Repo, Path: env.ChartPath, Ref: ref}, overrides: true})
+	}
+	sources = append(sources, envSource{file: github.FileRef{Repo: env.Repo, Path: _defaultHelmOverridesFilePath, Ref: ref},
+		overrides: true, optional: true})
 
-		overrides, err := NewParser(content).Load()
-		if err != nil {
-			return err
-		}
-		env.Overrides = overrides
+	files := make([]github.FileRef, 0, len(sources))
+	for _, source := range sources {
+		files = append(files, source.file)
 	}
+	contents, errs := s.getContents(ctx, pool, files)
 
-	if content, err := s.gh.GetContent(ctx, s.config.Github.Org,
-		env.Repo, _defaultHelmOverridesFilePath, s.config.Github.MainBranch); err == nil {
+	for index, source := range sources {
+		if errs[index] != nil {
+			if source.optional {
+				continue
+			}
+			return errs[index]
+		}
 
-		overrides, err := NewParser(content).Load()
+		services, err := NewParser(contents[index]).Load()
 		if err != nil {
 			return err
 		}
-		env.Overrides = overrides
+		if source.overrides {
+			env.Overrides = services
+		} else {
+			env.Services = services
+		}
 	}
 	return nil
 }
 
+// getContents fetches the files of the org with a single GraphQL query when it is available, falling back to a
+// REST request per file run on the pool
+func (s *Service) getContents(ctx context.Context, pool util.Pool, files []github.FileRef) ([][]byte, util.Errors) {
+	if s.gh.GraphQL {
+		if contents, errs, err := s.gh.GetContents(ctx, s.config.Github.Org, files); err == nil {
+			return contents, errs
+		}
+	}
+
+	contents := make([][]byte, len(files))
+	errs := pool.Run(ctx, len(files), func(ctx context.Context, index int) error {
+		var err error
+		contents[index], err = s.gh.GetContent(ctx, s.config.Github.Org, files[index].Repo, files[index].Path, files[index].Ref)
+		return err
+	})
+	return contents, errs
+}
+
 func (s *Service) UpdateHelmVersion(ctx context.Context, env *models.Environment, githubDetails *github.Commit, version string) error {
 
 	version = strings.Trim(version, "v")
@@ -194,55 +270,65 @@ func (s *Service) UpdateHelmVersion(ctx context.Context, env *models.Environment
 	return nil
 }
 
-func (s *Service) GetHelmVersions(ctx context.Context, env *models.Environment, platIndex int) (models.Releases, error) {
+// GetHelmVersions returns the releases of the platform helm chart sorted by semver descending
+func (s *Service) GetHelmVersions(ctx context.Context, platIndex int) (models.Releases, error) {
 	plat := s.config.GetPlatform(platIndex)
 	if plat == nil {
 		return nil, util.ErrMissingPlat
 	}
 
-	repository, err := s.gh.GetReleases(ctx, s.config.Github.Org, plat.HelmChartRepo)
-	if err != nil {
-		return nil, err
-	}
-	arr := make([]*models.Release, 0, len(repository))
-
-	tagVersion := env.GetHCVersion()
-	for _, repo := range repository {
-		if *repo.TagName != tagVersion {
-			arr = append(arr, &models.Release{
-				Name:      plat.HelmChartRepo,
-				Version:   *repo.TagName,
-				Changelog: *repo.Body,
-				URL:       *repo.HTMLURL,
-			})
-		}
-	}
-
-	return arr, nil
+	return s.GetRepoReleases(ctx, plat.HelmChartRepo)
 }
 
 func (s *Service) GetPlat(ctx context.Context, platRepo string) (*models.Platform, error) {
 
+	if s.gh.GraphQL {
+		if latest, content, err := s.gh.GetLatestReleaseContent(ctx, s.config.Github.Org, platRepo, _defaultChatServicesFilePath); err == nil {
+			release := newRelease(platRepo, latest)
+			services, err := s.parseChartServices(platRepo, release.Tag, content)
+			if err != nil {
+				return nil, err
+			}
+			return &models.Platform{Release: release, Services: services}, nil
+		}
+	}
+
 	latest, err := s.GetLatest(ctx, platRepo)
 	if err != nil {
 		return nil, err
 	}
 
-	plat := models.Platform{Release: latest}
-
-	content, err := s.gh.GetContent(ctx, s.config.Github.Org, platRepo, _defaultChatServicesFilePath, latest.Version)
+	services, err := s.getChartServices(ctx, platRepo, latest.Tag)
 	if err != nil {
 		return nil, err
 	}
 
-	parser := NewParser(content)
+	return &models.Platform{Release: latest, Services: services}, nil
+}
+
+// getChartServices returns the services of the helm chart at the tag, from its snapshot once it has been loaded
+func (s *Service) getChartServices(ctx context.Context, repo, tag string) (models.Services, error) {
+	if services, ok := s.snapshots.Load(s.config.Github.Org, repo, tag); ok {
+		return services, nil
+	}
 
-	plat.Services, err = parser.Load()
+	content, err := s.gh.GetContent(ctx, s.config.Github.Org, repo, _defaultChatServicesFilePath, tag)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseChartServices(repo, tag, content)
+}
+
+// parseChartServices parses the services of the helm chart at the tag and saves their snapshot
+func (s *Service) parseChartServices(repo, tag string, content []byte) (models.Services, error) {
+	services, err := NewParser(content).Load()
 	if err != nil {
 		return nil, err
 	}
 
-	return &plat, nil
+	// a snapshot failing to be saved is loaded again next time
+	_ = s.snapshots.Save(s.config.Github.Org, repo, tag, content, services)
+	return services, nil
 }
 
 func (s *Service) UpdateServicesVersions(ctx context.Context, platCfg *models.PlatformConfig, githubDetails *github.Commit, servicesUpdated []*models.ServiceUpdated) error {
@@ -293,197 +379,355 @@ func (s *Service) UpdateServicesVersions(ctx context.Context, platCfg *models.Pl
 		s.config.Github.MainBranch, githubDetails.AuthorName, githubDetails.AuthorEmail, githubDetails.Message, githubDetails.PullRequestTitle, githubDetails.PullRequestDescription)
 }
 
-func (s *Service) ComparePlatReleasesByVersion(ctx context.Context, platCfg *models.PlatformConfig, releases models.Releases, version string, version2 string) (*models.Comparer, error) {
-	resultsChan := make(chan *models.Platform)
+// VersionError is the error of loading the chart of a version
+type VersionError struct {
+	Version string
+	Err     error
+}
 
-	for _, v := range []string{version, version2} {
-		go func(version string) {
-			content, err := s.gh.GetContent(ctx, s.config.Github.Org, platCfg.HelmChartRepo, _defaultChatServicesFilePath, version)
-			if err != nil {
-				fmt.Println(err)
-				resultsChan <- nil
-			}
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("chart version %s %v", e.Version, e.Err)
+}
 
-			parser := NewParser(content)
+func (e *VersionError) Unwrap() error {
+	return e.Err
+}
 
-			services, err := parser.Load()
-			if err != nil {
-				fmt.Println(err)
-				resultsChan <- nil
-			}
+func (s *Service) ComparePlatReleasesByVersion(ctx context.Context, platCfg *models.PlatformConfig, releases models.Releases, version string, version2 string) (*models.Comparer, error) {
+	diffs, err := s.ComparePlatReleases(ctx, platCfg, releases, version, version2)
+	if err != nil {
+		return nil, err
+	}
+	return diffs[0], nil
+}
 
-			resultsChan <- &models.Platform{
-				Release:  releases.GetReleaseByVersion(version),
-				Services: services,
-			}
-		}(v)
+// ComparePlatReleases compares each version with the next one, e.g. the versions of a release train
+// v1.2.0 -> v1.3.0 -> v1.4.0 return the diffs of v1.2.0 -> v1.3.0 and v1.3.0 -> v1.4.0
+func (s *Service) ComparePlatReleases(ctx context.Context, platCfg *models.PlatformConfig, releases models.Releases, versions ...string) ([]*models.Comparer, error) {
+	if len(versions) < 2 {
+		return nil, errors.New("at least two versions are needed to compare")
+	}
+
+	plats, err := s.GetPlatReleases(ctx, platCfg, releases, versions...)
+	if err != nil {
+		return nil, err
 	}
 
-	var results []*models.Platform
-	for {
-		result := <-resultsChan
+	diffs := make([]*models.Comparer, 0, len(plats)-1)
+	for i := 1; i < len(plats); i++ {
+		diff := models.Compare(plats[i-1], plats[i])
+		diff.Platform = platCfg.Name
+		diff.Repo = platCfg.HelmChartRepo
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
 
-		if result == nil {
-			return nil, errors.New("could not get content for specified versions")
+// GetPlatReleases loads the chart services of each version at once, the first version failing cancels the
+// others and is returned as a *VersionError
+func (s *Service) GetPlatReleases(ctx context.Context, platCfg *models.PlatformConfig, releases models.Releases, versions ...string) ([]*models.Platform, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var failed error
+	plats := make([]*models.Platform, len(versions))
+	errs := s.Pool("Loading charts").Run(ctx, len(versions), func(ctx context.Context, index int) error {
+		version := versions[index]
+		services, err := s.getChartServices(ctx, platCfg.HelmChartRepo, version)
+		if err != nil {
+			err = &VersionError{Version: version, Err: err}
+			once.Do(func() {
+				failed = err
+				cancel()
+			})
+			return err
 		}
 
-		results = append(results, result)
-		// if we've reached the expected amount of results then stop
-		if len(results) == 2 {
-			break
+		plats[index] = &models.Platform{
+			Release:  releases.GetReleaseByVersion(version),
+			Services: services,
 		}
+		return nil
+	})
+	if failed != nil {
+		return nil, failed
 	}
-
-	return models.Compare(results[0], results[1]), nil
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return plats, nil
 }
 
-func (s Service) GetChangelogsFromDiff(ctx context.Context, diff *models.Comparer) (map[string]string, error) {
+func (s Service) GetChangelogsFromDiff(ctx context.Context, diff *models.Comparer) (models.Changelogs, error) {
+	changelogs, _, err := s.getChangelogsFromDiff(ctx, diff)
+	return changelogs, err
+}
 
-	changelogs := make(map[string]string, len(diff.Changed)+len(diff.Insert))
-	for serviceName, changed := range diff.Changed {
+// getChangelogsFromDiff returns the changelogs of the diff and the key of the changelog of each removed service
+func (s Service) getChangelogsFromDiff(ctx context.Context, diff *models.Comparer) (models.Changelogs, map[string]string, error) {
 
-		repoName, multi := s.ServiceNameToKebabCase(serviceName)
+	// each changelog is fetched once, changed services first as inserted services may share their repo
+	type changelogTask struct {
+		key   string
+		fetch func(ctx context.Context) (*models.Changelog, error)
+	}
+	var tasks []changelogTask
+	keys := make(map[string]bool)
 
-		if _, ok := changelogs[repoName]; !ok {
+	for _, serviceName := range diff.ChangedNames() {
+		changed := diff.Changed[serviceName]
+		resolved := s.ResolveService(serviceName)
+		if resolved.Ignore || keys[resolved.Key()] {
+			continue
+		}
+		keys[resolved.Key()] = true
 
-			version1 := changed.Service.Version
-			version2 := changed.NewVersion
-			if multi {
-				version1 = fmt.Sprintf("%s-%s", stringy.New(serviceName).KebabCase().ToLower(), version1)
-				version2 = fmt.Sprintf("%s-%s", stringy.New(serviceName).KebabCase().ToLower(), version2)
-			}
-			resp, err := s.gh.GetChangelog(ctx, s.config.Github.Org, repoName, version1, version2)
-			if err != nil {
-				return nil, err
-			}
+		version1 := resolved.Tag(changed.Service.Version)
+		version2 := resolved.Tag(changed.NewVersion)
+		tasks = append(tasks, changelogTask{key: resolved.Key(), fetch: func(ctx context.Context) (*models.Changelog, error) {
+			return s.gh.GetChangelog(ctx, s.config.Github.Org, resolved.Repo, version1, version2)
+		}})
+	}
 
-			changelogs[repoName] = resp.Body
+	for _, serviceName := range diff.Insert.Names() {
+		service := diff.Insert[serviceName]
+		resolved := s.ResolveService(serviceName)
+		if resolved.Ignore || keys[resolved.Key()] {
+			continue
 		}
+		keys[resolved.Key()] = true
 
+		serviceName := serviceName
+		tasks = append(tasks, changelogTask{key: resolved.Key(), fetch: func(ctx context.Context) (*models.Changelog, error) {
+			return s.getInsertedChangelog(ctx, serviceName, service.Version)
+		}})
 	}
 
-	for serviceName, service := range diff.Insert {
+	removedKeys := make(map[string]string, len(diff.Deleted))
+	for _, serviceName := range diff.Deleted.Names() {
+		service := diff.Deleted[serviceName]
+		resolved := s.ResolveService(serviceName)
+		if resolved.Ignore {
+			continue
+		}
 
-		repoName, _ := s.ServiceNameToKebabCase(serviceName)
-		releases, err := s.GetRepoReleases(ctx, repoName)
-		if err != nil {
-			return nil, err
+		// the repo may still hold the changelog of another service, the removed service keeps its own entry
+		key := resolved.Key()
+		if keys[key] {
+			key = serviceName
 		}
+		keys[key] = true
+		removedKeys[serviceName] = key
 
-		var builder strings.Builder
-		release := releases.GetReleaseByVersion(service.Version)
-		builder.WriteString(release.Changelog)
+		serviceName := serviceName
+		tasks = append(tasks, changelogTask{key: key, fetch: func(ctx context.Context) (*models.Changelog, error) {
+			return s.getRemovedChangelog(ctx, serviceName, resolved, service.Version)
+		}})
+	}
 
-		for _, r := range releases {
-			if r.Date.Before(release.Date) {
-				builder.WriteString(r.Changelog)
-			}
+	fetched := make([]*models.Changelog, len(tasks))
+	errs := s.Pool("Generating changelogs").Run(ctx, len(tasks), func(ctx context.Context, index int) error {
+		changelog, err := tasks[index].fetch(ctx)
+		if err != nil {
+			return fmt.Errorf("%s %w", tasks[index].key, err)
 		}
+		fetched[index] = changelog
+		return nil
+	})
+	if err := errs.Err(); err != nil {
+		return nil, nil, err
+	}
 
-		changelogs[repoName] = builder.String()
-
+	changelogs := make(models.Changelogs, len(tasks))
+	for index, task := range tasks {
+		changelogs[task.key] = fetched[index]
 	}
+	return changelogs, removedKeys, nil
+}
 
-	return changelogs, nil
+// getRemovedChangelog returns the changelog of a service removed from a chart, with the release of its last
+// deployed version when it has one
+func (s Service) getRemovedChangelog(ctx context.Context, serviceName string, resolved models.ResolvedService, version string) (*models.Changelog, error) {
+	tag := resolved.Tag(version)
+	changelog := &models.Changelog{
+		Org:     s.config.Github.Org,
+		Repo:    resolved.Repo,
+		To:      tag,
+		Removed: true,
+	}
+
+	note := fmt.Sprintf("%s removed in this release, last deployed version %s", serviceName, version)
+	release, err := s.gh.GetRelease(ctx, s.config.Github.Org, resolved.Repo, tag)
+	switch {
+	case err == nil:
+		changelog.URL = release.GetHTMLURL()
+		note = fmt.Sprintf("%s %s", note, changelog.URL)
+	case !github.IsNotFound(err):
+		return nil, fmt.Errorf("getting release %s of %s %w", tag, resolved.Repo, err)
+	}
+	changelog.Notes = []string{note}
+	return changelog, nil
 }
 
-func (s Service) ExportRelease(ctx context.Context, diff *models.Comparer) error {
+// getInsertedChangelog returns the changelog of a service newly added to a chart, from the
+// configured baseline or its first release up to the deployed version
+func (s Service) getInsertedChangelog(ctx context.Context, serviceName, version string) (*models.Changelog, error) {
 
-	if _, err := os.Stat(_defaultReleasesPath); errors.Is(err, os.ErrNotExist) {
-		err := os.Mkdir(_defaultReleasesPath, os.ModePerm)
-		if err != nil {
-			log.Println(err)
-		}
+	resolved := s.ResolveService(serviceName)
+	repoName := resolved.Repo
+
+	tag := resolved.Tag(version)
+	baseline := ""
+	if resolved.Mapping.Baseline != "" {
+		baseline = resolved.Tag(resolved.Mapping.Baseline)
 	}
 
-	releaseFilePath := fmt.Sprintf("%s/release-%s-%s-%s", _defaultReleasesPath, diff.InitialVersion, diff.FinalVersion, time.Now().UTC().Format("02-01-06"))
-	if _, err := os.Stat(releaseFilePath); errors.Is(err, os.ErrNotExist) {
-		if err := os.Mkdir(releaseFilePath, os.ModePerm); err != nil {
-			log.Println(err)
-		}
+	changelog := &models.Changelog{
+		Org:  s.config.Github.Org,
+		Repo: repoName,
+		From: baseline,
+		To:   tag,
 	}
 
-	f, err := os.OpenFile(filepath.Join(releaseFilePath, _defaultReleaseFileName), os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	releases, err := s.GetServiceReleases(ctx, serviceName)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	diff.DisableColor = true
-	if _, err := f.WriteString(diff.String()); err != nil {
-		return err
+	release := releases.GetReleaseByVersion(version)
+	if release == nil {
+		changelog.Notes = append(changelog.Notes, fmt.Sprintf("release %s not found in %s", tag, repoName))
+		return changelog, nil
 	}
-	if err := f.Close(); err != nil {
-		return err
+
+	var first *models.Release
+	if baseline == "" {
+		first = releases.Oldest()
+		changelog.From = first.Tag
 	}
 
-	changelogs, err := s.GetChangelogsFromDiff(ctx, diff)
-	if err != nil {
-		return err
+	if changelog.From == tag {
+		changelog.Changes = models.ParseReleaseNotes(release.Changelog)
+		return changelog, nil
 	}
 
-	for service, changelog := range changelogs {
+	ranged, err := s.gh.GetChangelog(ctx, s.config.Github.Org, repoName, changelog.From, tag)
+	if err != nil {
+		return nil, err
+	}
 
-		filename := fmt.Sprintf("%s_changelog.txt", service)
-		f, err := os.OpenFile(filepath.Join(releaseFilePath, filename), os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Fatal(err)
-		}
-		if _, err := f.WriteString(changelog); err != nil {
-			return err
-		}
-		if err := f.Close(); err != nil {
-			return err
-		}
+	// the range excludes the starting release, when it is the first release its own notes are included
+	if first != nil {
+		changelog.Changes = models.ParseReleaseNotes(first.Changelog)
 	}
-	return nil
+	changelog.Changes = append(changelog.Changes, ranged.Changes...)
 
+	return changelog, nil
 }
 
-func (s Service) GetAvailableServiceReleases(ctx context.Context, service *models.Service) (models.Releases, error) {
-
-	repoName, multi := s.ServiceNameToKebabCase(service.Name)
+// GetUpdateChangelog returns the changelog of a service from its current version to a newer release
+func (s *Service) GetUpdateChangelog(ctx context.Context, service *models.Service, release *models.Release) (*models.Changelog, error) {
+	resolved := s.ResolveService(service.Name)
+	return s.gh.GetChangelog(ctx, s.config.Github.Org, resolved.Repo, resolved.Tag(service.Version), release.Tag)
+}
 
-	version := service.Version
-	if multi {
-		version = fmt.Sprintf("%s-%s", stringy.New(service.Name).KebabCase().Get(), version)
-	}
+// GetAvailableServiceReleases returns the service releases published after its current version
+func (s Service) GetAvailableServiceReleases(ctx context.Context, service *models.Service) (models.Releases, error) {
 
-	release, err := s.gh.GetRelease(ctx, s.config.Github.Org, repoName, version)
+	releases, err := s.GetServiceReleases(ctx, service.Name)
 	if err != nil {
 		return nil, err
 	}
+	return availableReleases(service, releases), nil
+}
 
-	repoReleases, err := s.gh.GetReleases(ctx, s.config.Github.Org, repoName)
-	if err != nil {
-		return nil, err
+// GetAvailableServicesReleases returns the available releases of each service, the releases of every repo are
+// fetched with a single GraphQL query when it is available, falling back to REST requests per service
+func (s Service) GetAvailableServicesReleases(ctx context.Context, services []*models.Service) ([]models.Releases, util.Errors) {
+	available := make([]models.Releases, len(services))
+
+	if s.gh.GraphQL {
+		var repos []string
+		repoIndex := make(map[string]int)
+		for _, service := range services {
+			resolved := s.ResolveService(service.Name)
+			if _, ok := repoIndex[resolved.Repo]; !resolved.Ignore && !ok {
+				repoIndex[resolved.Repo] = len(repos)
+				repos = append(repos, resolved.Repo)
+			}
+		}
+
+		if byRepo, repoErrs, err := s.gh.GetReposReleases(ctx, s.config.Github.Org, repos); err == nil {
+			errs := make(util.Errors, len(services))
+			for index, service := range services {
+				resolved := s.ResolveService(service.Name)
+				if resolved.Ignore {
+					errs[index] = fmt.Errorf("%s %w", service.Name, util.ErrIgnoredService)
+					continue
+				}
+				i := repoIndex[resolved.Repo]
+				if repoErrs[i] != nil {
+					errs[index] = fmt.Errorf("%s %w", service.Name, repoErrs[i])
+					continue
+				}
+				// each service gets its own releases, multi tag repos change their versions
+				releases := serviceReleases(resolved, newReleases(resolved.Repo, byRepo[i]))
+				available[index] = availableReleases(service, releases)
+			}
+			return available, errs
+		}
 	}
-	releases := make([]*models.Release, 0, len(repoReleases))
 
-	for _, repo := range repoReleases {
+	errs := s.Pool("Obtaining available versions").Run(ctx, len(services), func(ctx context.Context, index int) error {
+		releases, err := s.GetAvailableServiceReleases(ctx, services[index])
+		if err != nil {
+			return fmt.Errorf("%s %w", services[index].Name, err)
+		}
+		available[index] = releases
+		return nil
+	})
+	return available, errs
+}
 
-		if release.CreatedAt.After(repo.CreatedAt.Time) || release.CreatedAt.Equal(*repo.CreatedAt) {
-			continue
+// availableReleases keeps the releases published after the current version of the service, the releases of a
+// version that is not released are the ones with a greater semver
+func availableReleases(service *models.Service, releases models.Releases) models.Releases {
+	current := releases.GetReleaseByVersion(service.Version)
+
+	available := make(models.Releases, 0, len(releases))
+	for _, release := range releases {
+		if current != nil && release.Date.After(current.Date) ||
+			current == nil && models.CompareVersions(release.Version, service.Version) > 0 {
+			available = append(available, release)
 		}
-		releases = append(releases, &models.Release{
-			Name:      repoName,
-			Version:   *repo.TagName,
-			Changelog: *repo.Body,
-			URL:       *repo.HTMLURL,
-			Date:      repo.CreatedAt.Time,
-		})
 	}
+	return available
+}
 
-	return releases, err
+// ResolveService returns the repo of a service by its chart name using the configured service rules
+func (s Service) ResolveService(serviceName string) models.ResolvedService {
+	return s.config.Services.Resolve(serviceName)
 }
 
-func (s Service) ServiceNameToKebabCase(serviceName string) (string, bool) {
-	repoName := stringy.New(serviceName).KebabCase().Get()
-	multiTag := false
-	for regex, mapping := range s.config.ServicesMapping {
-		if matched, _ := regexp.MatchString(regex, serviceName); matched {
-			repoName = mapping.Repo
-			multiTag = mapping.MultiTag
-		}
+// newReleases returns the releases of a repo sorted by semver descending
+func newReleases(name string, releases []*gogithub.RepositoryRelease) models.Releases {
+	arr := make(models.Releases, 0, len(releases))
+	for _, release := range releases {
+		arr = append(arr, newRelease(name, release))
+	}
+	arr.Sort()
+	return arr
+}
+
+func newRelease(name string, release *gogithub.RepositoryRelease) *models.Release {
+	return &models.Release{
+		Name:       name,
+		Version:    release.GetTagName(),
+		Tag:        release.GetTagName(),
+		Changelog:  release.GetBody(),
+		URL:        release.GetHTMLURL(),
+		Date:       release.GetPublishedAt().Time,
+		Prerelease: release.GetPrerelease(),
 	}
-	return strings.ToLower(repoName), multiTag
 }
@@ -2,13 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/adam-putland/divido-cli/internal/models"
+	utils "github.com/adam-putland/divido-cli/internal/util"
 	util "github.com/adam-putland/divido-cli/internal/util/github"
 	"github.com/adam-putland/divido-cli/internal/util/github/mock"
 	"github.com/google/go-github/v45/github"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestService_GetServiceLatest(t *testing.T) {
@@ -46,6 +50,7 @@ func TestService_GetServiceLatest(t *testing.T) {
 			want: &models.Release{
 				Name:      "foobar",
 				Version:   "v1.0.0",
+				Tag:       "v1.0.0",
 				Changelog: "body",
 				URL:       "url",
 			},
@@ -88,3 +93,431 @@ func TestService_GetServiceLatest(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetInsertedChangelog(t *testing.T) {
+
+	config := models.Config{
+		Github: models.GithubConfig{Org: "test"},
+		Services: models.ServiceRules{
+			{Match: ".*GraphqlApi.*", ServiceMapping: models.ServiceMapping{Repo: "graphql-apis", MultiTag: true}},
+		},
+	}
+	if err := config.Services.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	releases := []github.RepositoryRelease{
+		{
+			TagName:     github.String("lender-graphql-api-v1.0.0"),
+			HTMLURL:     github.String("url"),
+			Body:        github.String("* feat: lenders by @jane in https://github.com/test/graphql-apis/pull/2"),
+			PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			TagName:     github.String("applicant-graphql-api-v1.0.0"),
+			HTMLURL:     github.String("url"),
+			Body:        github.String("* feat: applicants by @jane in https://github.com/test/graphql-apis/pull/1"),
+			PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		serviceName string
+		version     string
+		want        *models.Changelog
+	}{
+		{
+			name:        "first_release_of_multi_tag_service",
+			serviceName: "lenderGraphqlApi",
+			version:     "v1.0.0",
+			want: &models.Changelog{Org: "test", Repo: "graphql-apis", From: "lender-graphql-api-v1.0.0", To: "lender-graphql-api-v1.0.0",
+				Changes: []*models.Change{{Type: models.ChangeFeat, Description: "lenders", PullRequest: 2, Author: "jane", Message: "feat: lenders"}}},
+		},
+		{
+			name:        "release_not_found",
+			serviceName: "lenderGraphqlApi",
+			version:     "v2.0.0",
+			want: &models.Changelog{Org: "test", Repo: "graphql-apis", To: "lender-graphql-api-v2.0.0",
+				Notes: []string{"release lender-graphql-api-v2.0.0 not found in graphql-apis"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := Service{
+				gh: &util.GithubClient{
+					Client: github.NewClient(mock.NewMockedHTTPClient(
+						mock.WithRequestMatch(mock.GetReposReleasesByOwnerByRepo, releases),
+					)),
+				},
+				config: &config,
+			}
+
+			got, err := s.getInsertedChangelog(context.Background(), tt.serviceName, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getInsertedChangelog() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_GetChangelogsFromDiffMultiTag(t *testing.T) {
+
+	config := models.Config{
+		Github: models.GithubConfig{Org: "test"},
+		Services: models.ServiceRules{
+			{Match: ".*GraphqlApi.*", ServiceMapping: models.ServiceMapping{Repo: "graphql-apis", MultiTag: true}},
+		},
+	}
+	if err := config.Services.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	releases := []github.RepositoryRelease{
+		{
+			TagName:     github.String("applicant-graphql-api-v1.0.0"),
+			HTMLURL:     github.String("url"),
+			Body:        github.String("* feat: applicants by @jane in https://github.com/test/graphql-apis/pull/1"),
+			PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	notes := github.RepositoryReleaseNotes{Body: "* feat: lenders by @jane in https://github.com/test/graphql-apis/pull/2"}
+
+	s := Service{
+		gh: &util.GithubClient{
+			Client: github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.PostReposReleasesGenerateNotesByOwnerByRepo, notes),
+				mock.WithRequestMatch(mock.GetReposReleasesByOwnerByRepo, releases),
+			)),
+		},
+		config: &config,
+	}
+
+	diff := &models.Comparer{
+		Changed: map[string]*models.ServiceUpdated{
+			"lenderGraphqlApi": {Service: &models.Service{Release: models.Release{Version: "v1.0.0"}}, NewVersion: "v1.1.0"},
+		},
+		Insert: models.Services{
+			"applicantGraphqlApi": &models.Service{Release: models.Release{Version: "v1.0.0"}},
+		},
+	}
+
+	got, err := s.GetChangelogsFromDiff(context.Background(), diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"lender-graphql-api":    "lender-graphql-api-v1.1.0",
+		"applicant-graphql-api": "applicant-graphql-api-v1.0.0",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetChangelogsFromDiff() got = %v, want %v", got.Repos(), want)
+	}
+	for key, to := range want {
+		if got[key] == nil || got[key].To != to || got[key].Repo != "graphql-apis" {
+			t.Errorf("GetChangelogsFromDiff() got = %+v, want %v to %v", got[key], key, to)
+		}
+	}
+}
+
+func TestService_GetChangelogsFromDiffDeleted(t *testing.T) {
+
+	config := models.Config{
+		Github: models.GithubConfig{Org: "test"},
+		Services: models.ServiceRules{
+			{Match: ".*GraphqlApi.*", ServiceMapping: models.ServiceMapping{Repo: "graphql-apis", MultiTag: true}},
+		},
+	}
+	if err := config.Services.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		status    int
+		wantNotes []string
+		wantURL   string
+		wantErr   bool
+	}{
+		{name: "release_found", status: http.StatusOK, wantURL: "url",
+			wantNotes: []string{"applicantGraphqlApi removed in this release, last deployed version v1.0.0 url"}},
+		{name: "release_not_found", status: http.StatusNotFound,
+			wantNotes: []string{"applicantGraphqlApi removed in this release, last deployed version v1.0.0"}},
+		{name: "server_error", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := Service{
+				gh: &util.GithubClient{
+					Client: github.NewClient(mock.NewMockedHTTPClient(
+						mock.WithRequestMatch(mock.PostReposReleasesGenerateNotesByOwnerByRepo,
+							github.RepositoryReleaseNotes{Body: "* feat: lenders by @jane in https://github.com/test/graphql-apis/pull/2"},
+							github.RepositoryReleaseNotes{Body: "* feat: lenders by @jane in https://github.com/test/graphql-apis/pull/2"}),
+						mock.WithRequestMatchHandler(mock.GetReposReleasesTagsByOwnerByRepoByTag,
+							http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+								if tt.status != http.StatusOK {
+									// mock.WriteError encodes a null Response which hides the status of the error
+									w.WriteHeader(tt.status)
+									w.Write(mock.MustMarshal(map[string]string{"message": http.StatusText(tt.status)}))
+									return
+								}
+								w.Write(mock.MustMarshal(github.RepositoryRelease{HTMLURL: github.String("url")}))
+							})),
+					)),
+				},
+				config: &config,
+			}
+
+			deleted := &models.Service{Release: models.Release{Version: "v1.0.0"}}
+			diff := &models.Comparer{
+				Changed: map[string]*models.ServiceUpdated{
+					"lenderGraphqlApi": {Service: &models.Service{Release: models.Release{Version: "v1.0.0"}}, NewVersion: "v1.1.0"},
+				},
+				Deleted: models.Services{"applicantGraphqlApi": deleted},
+			}
+
+			got, err := s.GetChangelogsFromDiff(context.Background(), diff)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetChangelogsFromDiff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if notes := got["lender-graphql-api"].Notes; len(notes) != 0 {
+				t.Errorf("GetChangelogsFromDiff() got notes %v on the changed service", notes)
+			}
+			removed := got["applicant-graphql-api"]
+			if removed == nil || !removed.Removed || !reflect.DeepEqual(removed.Notes, tt.wantNotes) || removed.URL != tt.wantURL {
+				t.Errorf("GetChangelogsFromDiff() got = %+v, want notes %v and url %q", removed, tt.wantNotes, tt.wantURL)
+			}
+
+			// the rendered release links the last version of the removed service, the diff is left as is
+			_, files, err := s.RenderRelease(context.Background(), diff, FormatMarkdown)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantLine := "- applicantGraphqlApi: v1.0.0 (removed in this release)"
+			if tt.wantURL != "" {
+				wantLine = "- applicantGraphqlApi: [v1.0.0](url) (removed in this release)"
+			}
+			if !strings.Contains(string(files[0].Content), wantLine) {
+				t.Errorf("RenderRelease() got = %s, want to contain %s", files[0].Content, wantLine)
+			}
+			if deleted.URL != "" {
+				t.Errorf("RenderRelease() set the url of the deleted service of the diff to %v", deleted.URL)
+			}
+		})
+	}
+}
+
+func TestService_ComparePlatReleases(t *testing.T) {
+
+	config := models.Config{Github: models.GithubConfig{Org: "test"}}
+	charts := map[string]string{
+		"v1.0.0": "services:\n  applicationApi:\n    serviceVersion: v1.0.0\n",
+		"v1.1.0": "services:\n  applicationApi:\n    serviceVersion: v1.1.0\n",
+		"v1.2.0": "services:\n  applicationApi:\n    serviceVersion: v1.1.0\n  lenderApi:\n    serviceVersion: v1.0.0\n",
+	}
+
+	releases := models.Releases{{Version: "v1.0.0"}, {Version: "v1.1.0"}, {Version: "v1.2.0"}}
+
+	tests := []struct {
+		name        string
+		versions    []string
+		wantChanged []int
+		wantInsert  []int
+		wantVersion string
+	}{
+		{name: "two_versions", versions: []string{"v1.0.0", "v1.1.0"}, wantChanged: []int{1}, wantInsert: []int{0}},
+		{name: "release_train", versions: []string{"v1.0.0", "v1.1.0", "v1.2.0"}, wantChanged: []int{1, 0}, wantInsert: []int{0, 1}},
+		{name: "missing_version", versions: []string{"v1.0.0", "v9.9.9", "v1.2.0"}, wantVersion: "v9.9.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := Service{
+				gh: &util.GithubClient{
+					Client: github.NewClient(mock.NewMockedHTTPClient(
+						mock.WithRequestMatchHandler(
+							mock.GetReposContentsByOwnerByRepoByPath,
+							http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+								chart, ok := charts[r.URL.Query().Get("ref")]
+								if !ok {
+									mock.WriteError(w, http.StatusNotFound, "not found")
+									return
+								}
+								w.Write(mock.MustMarshal(github.RepositoryContent{
+									Type:    github.String("file"),
+									Content: github.String(chart),
+								}))
+							}),
+						))),
+				},
+				config: &config,
+			}
+
+			diffs, err := s.ComparePlatReleases(context.Background(), &models.PlatformConfig{Name: "ing", HelmChartRepo: "ing-platform-hlm"}, releases, tt.versions...)
+			if tt.wantVersion != "" {
+				var versionErr *VersionError
+				if !errors.As(err, &versionErr) || versionErr.Version != tt.wantVersion {
+					t.Errorf("ComparePlatReleases() error = %v, want version %v", err, tt.wantVersion)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var changed, insert []int
+			for _, diff := range diffs {
+				changed = append(changed, len(diff.Changed))
+				insert = append(insert, len(diff.Insert))
+			}
+			if !reflect.DeepEqual(changed, tt.wantChanged) || !reflect.DeepEqual(insert, tt.wantInsert) {
+				t.Errorf("ComparePlatReleases() got changed = %v insert = %v, want %v %v", changed, insert, tt.wantChanged, tt.wantInsert)
+			}
+		})
+	}
+}
+
+func TestService_GetAvailableServicesReleases(t *testing.T) {
+
+	config := models.Config{
+		Github: models.GithubConfig{Org: "test"},
+		Services: models.ServiceRules{
+			{Match: ".*GraphqlApi.*", ServiceMapping: models.ServiceMapping{Repo: "graphql-apis", MultiTag: true}},
+			{Name: "legacyPortal", ServiceMapping: models.ServiceMapping{Repo: "legacy-portal"}, Ignore: true},
+		},
+	}
+	if err := config.Services.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	services := []*models.Service{
+		{Release: models.Release{Name: "lenderGraphqlApi", Version: "v1.0.0"}},
+		{Release: models.Release{Name: "applicantGraphqlApi", Version: "v2.0.0"}},
+		{Release: models.Release{Name: "legacyPortal", Version: "v1.0.0"}},
+	}
+
+	releases := []github.RepositoryRelease{
+		{TagName: github.String("lender-graphql-api-v1.1.0"), PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC)}},
+		{TagName: github.String("applicant-graphql-api-v2.0.0"), PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)}},
+		{TagName: github.String("lender-graphql-api-v1.0.0"), PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	graphqlReleases := `{"data": {"r0": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": [
+		{"tagName": "lender-graphql-api-v1.1.0", "publishedAt": "2022-06-03T00:00:00Z"},
+		{"tagName": "applicant-graphql-api-v2.0.0", "publishedAt": "2022-06-02T00:00:00Z"},
+		{"tagName": "lender-graphql-api-v1.0.0", "publishedAt": "2022-06-01T00:00:00Z"}]}}}}`
+
+	tests := []struct {
+		name    string
+		graphql bool
+		options []mock.MockBackendOption
+	}{
+		{name: "rest", options: []mock.MockBackendOption{mock.WithRequestMatch(mock.GetReposReleasesByOwnerByRepo, releases, releases)}},
+		{name: "graphql", graphql: true, options: []mock.MockBackendOption{mock.WithRequestMatchHandler(mock.PostGraphql,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(graphqlReleases))
+			}))}},
+		// the mocked backend has no GraphQL endpoint
+		{name: "graphql_fallback", graphql: true, options: []mock.MockBackendOption{mock.WithRequestMatch(mock.GetReposReleasesByOwnerByRepo, releases, releases)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := Service{
+				gh: &util.GithubClient{
+					Client:  github.NewClient(mock.NewMockedHTTPClient(tt.options...)),
+					GraphQL: tt.graphql,
+				},
+				config: &config,
+			}
+
+			available, errs := s.GetAvailableServicesReleases(context.Background(), services)
+
+			var versions [][]string
+			for _, releases := range available {
+				versions = append(versions, releases.Versions())
+			}
+			want := [][]string{{"v1.1.0"}, {}, {}}
+			if !reflect.DeepEqual(versions, want) {
+				t.Errorf("GetAvailableServicesReleases() got = %v, want %v", versions, want)
+			}
+
+			for index, err := range errs {
+				if wantIgnored := index == 2; errors.Is(err, utils.ErrIgnoredService) != wantIgnored {
+					t.Errorf("GetAvailableServicesReleases() service %d error = %v, want ignored %v", index, err, wantIgnored)
+				}
+			}
+		})
+	}
+}
+
+func TestService_GetAvailableServicesReleasesMissingRepo(t *testing.T) {
+
+	config := models.Config{Github: models.GithubConfig{Org: "test"}}
+	services := []*models.Service{{Release: models.Release{Name: "merchantPortal", Version: "v1.0.0"}}}
+
+	tests := []struct {
+		name    string
+		graphql bool
+		options []mock.MockBackendOption
+	}{
+		// the mocked backend answers 404 to the unmatched releases request
+		{name: "rest"},
+		{name: "graphql", graphql: true, options: []mock.MockBackendOption{mock.WithRequestMatchHandler(mock.PostGraphql,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"data": {"r0": null}, "errors": [{"type": "NOT_FOUND", "message": "not found"}]}`))
+			}))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := Service{
+				gh: &util.GithubClient{
+					Client:  github.NewClient(mock.NewMockedHTTPClient(tt.options...)),
+					GraphQL: tt.graphql,
+				},
+				config: &config,
+			}
+
+			_, errs := s.GetAvailableServicesReleases(context.Background(), services)
+			if errs[0] == nil {
+				t.Errorf("GetAvailableServicesReleases() error = %v, want an error for the missing repo", errs[0])
+			}
+		})
+	}
+}
+
+func TestAvailableReleases(t *testing.T) {
+
+	releases := models.Releases{
+		{Version: "v1.2.0", Date: time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC)},
+		{Version: "v1.1.0", Date: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{Version: "v1.0.0", Date: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		want    models.Versions
+	}{
+		{name: "released", version: "v1.1.0", want: models.Versions{"v1.2.0"}},
+		{name: "latest", version: "v1.2.0", want: models.Versions{}},
+		{name: "not_released", version: "v1.0.5", want: models.Versions{"v1.2.0", "v1.1.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := availableReleases(&models.Service{Release: models.Release{Version: tt.version}}, releases)
+			if versions := got.Versions(); !reflect.DeepEqual(versions, tt.want) {
+				t.Errorf("availableReleases() got = %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// _snapshotsVersion is the format of the snapshots, it is bumped when models.Services changes so the snapshots
+// of older formats are not read
+const _snapshotsVersion = "v1"
+
+// _snapshotsDisabled makes DefaultSnapshots return nil, see DisableSnapshots
+var _snapshotsDisabled bool
+
+// Snapshots stores the parsed services of the helm charts by repo and tag, a released tag never changes so
+// snapshots never expire, they are cleared with Clear. Refs map a repo tag to the sha256 of its services file,
+// tags with the same file share its object
+type Snapshots struct {
+	Dir string
+}
+
+// DefaultSnapshots stores the snapshots in the user cache dir, nil when there is none or snapshots are disabled
+func DefaultSnapshots() *Snapshots {
+	if _snapshotsDisabled {
+		return nil
+	}
+
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return &Snapshots{Dir: filepath.Join(cache, "divido-cli", "snapshots")}
+}
+
+// DisableSnapshots makes the services created afterwards load every helm chart from GitHub, e.g. with --no-cache
+func DisableSnapshots() {
+	_snapshotsDisabled = true
+}
+
+// Load returns the services of the chart at the tag, false when it has no snapshot
+func (s *Snapshots) Load(org, repo, tag string) (models.Services, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	ref, err := os.ReadFile(s.refPath(org, repo, tag))
+	if err != nil {
+		return nil, false
+	}
+
+	sum := strings.TrimSpace(string(ref))
+	if len(sum) != sha256.Size*2 {
+		return nil, false
+	}
+
+	content, err := os.ReadFile(s.objectPath(sum))
+	if err != nil {
+		return nil, false
+	}
+
+	var services models.Services
+	if err := json.Unmarshal(content, &services); err != nil {
+		return nil, false
+	}
+	return services, true
+}
+
+// Save stores the services parsed from the chart content at the tag
+func (s *Snapshots) Save(org, repo, tag string, content []byte, services models.Services) error {
+	if s == nil {
+		return nil
+	}
+
+	object, err := json.Marshal(services)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+	sum := hex.EncodeToString(hash[:])
+	if err := writeFileAtomic(s.objectPath(sum), object); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.refPath(org, repo, tag), []byte(sum+"\n"))
+}
+
+// Clear removes every snapshot, of any format
+func (s *Snapshots) Clear() error {
+	if s == nil {
+		return nil
+	}
+	if err := os.RemoveAll(s.Dir); err != nil {
+		return fmt.Errorf("clearing snapshots %w", err)
+	}
+	return nil
+}
+
+func (s *Snapshots) refPath(org, repo, tag string) string {
+	hash := sha256.Sum256([]byte(tag))
+	return filepath.Join(s.Dir, _snapshotsVersion, "refs", org, repo, hex.EncodeToString(hash[:]))
+}
+
+func (s *Snapshots) objectPath(sum string) string {
+	return filepath.Join(s.Dir, _snapshotsVersion, "objects", sum[:2], sum+".json")
+}
+
+// writeFileAtomic writes the file through a temporary file so a concurrent Load never reads it half written
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating snapshot dir %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"github.com/adam-putland/divido-cli/internal/models"
+	util "github.com/adam-putland/divido-cli/internal/util/github"
+	"github.com/adam-putland/divido-cli/internal/util/github/mock"
+	"github.com/google/go-github/v45/github"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshots_Load(t *testing.T) {
+
+	services := models.Services{"applicationApi": {HLMName: "applicationApi", Release: models.Release{Name: "applicationApi", Version: "v1.0.0"}}}
+	content := []byte("services:\n  applicationApi:\n    serviceVersion: v1.0.0\n")
+
+	snapshots := &Snapshots{Dir: t.TempDir()}
+	for _, tag := range []string{"v1.0.0", "v1.0.1"} {
+		if err := snapshots.Save("test", "ing-platform-hlm", tag, content, services); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		repo   string
+		tag    string
+		want   models.Services
+		wantOk bool
+	}{
+		{name: "saved", repo: "ing-platform-hlm", tag: "v1.0.0", want: services, wantOk: true},
+		{name: "same_content", repo: "ing-platform-hlm", tag: "v1.0.1", want: services, wantOk: true},
+		{name: "missing_tag", repo: "ing-platform-hlm", tag: "v2.0.0"},
+		{name: "missing_repo", repo: "divido-platform-hlm", tag: "v1.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := snapshots.Load("test", tt.repo, tt.tag)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() got = %v %v, want %v %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	objects, err := filepath.Glob(filepath.Join(snapshots.Dir, _snapshotsVersion, "objects", "*", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 {
+		t.Errorf("Save() got = %v objects, want the tags sharing a single one", len(objects))
+	}
+
+	if err := snapshots.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snapshots.Load("test", "ing-platform-hlm", "v1.0.0"); ok {
+		t.Errorf("Load() of cleared snapshots got = %v, want false", ok)
+	}
+
+	var nilSnapshots *Snapshots
+	if _, ok := nilSnapshots.Load("test", "ing-platform-hlm", "v1.0.0"); ok {
+		t.Errorf("Load() of nil snapshots got = %v, want false", ok)
+	}
+}
+
+func TestService_GetPlatReleasesSnapshot(t *testing.T) {
+
+	services := models.Services{"applicationApi": {HLMName: "applicationApi", Release: models.Release{Name: "applicationApi", Version: "v1.0.0"}}}
+	snapshots := &Snapshots{Dir: t.TempDir()}
+	for _, tag := range []string{"v1.0.0", "v1.1.0"} {
+		if err := snapshots.Save("test", "ing-platform-hlm", tag, []byte(tag), services); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the mocked backend has no endpoint, the charts can only be loaded from their snapshots
+	s := Service{
+		gh:        &util.GithubClient{Client: github.NewClient(mock.NewMockedHTTPClient())},
+		config:    &models.Config{Github: models.GithubConfig{Org: "test"}},
+		snapshots: snapshots,
+	}
+
+	releases := models.Releases{{Version: "v1.0.0"}, {Version: "v1.1.0"}}
+	plats, err := s.GetPlatReleases(context.Background(), &models.PlatformConfig{Name: "ing", HelmChartRepo: "ing-platform-hlm"}, releases, "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, plat := range plats {
+		if plat.Release != releases[index] || !reflect.DeepEqual(plat.Services, services) {
+			t.Errorf("GetPlatReleases() got = %v, want %v", plat, services)
+		}
+	}
+
+	if _, err := s.GetPlatReleases(context.Background(), &models.PlatformConfig{Name: "ing", HelmChartRepo: "ing-platform-hlm"}, releases, "v1.2.0"); err == nil {
+		t.Errorf("GetPlatReleases() error = %v, want the missing snapshot fetched", err)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+)
+
+// RepoCheck is the result of checking a configured repo is reachable with the current token
+type RepoCheck struct {
+	Repo string
+	Err  error
+}
+
+// ValidateExport checks the default format and archive exist and every export template parses
+func (s Service) ValidateExport() []error {
+	var errs []error
+
+	formats := s.ExportFormats()
+	if !contains(formats, s.defaultExportFormat()) {
+		errs = append(errs, fmt.Errorf("export format %s is not defined", s.defaultExportFormat()))
+	}
+	if !contains(s.ArchiveOptions(), s.config.Export.Archive) {
+		errs = append(errs, fmt.Errorf("export archive %s must be %s or %s", s.config.Export.Archive, ArchiveTarGz, ArchiveZip))
+	}
+	if s.config.Export.Dir != "" {
+		if _, err := template.New("").Parse(s.config.Export.Dir); err != nil {
+			errs = append(errs, fmt.Errorf("export dir %w", err))
+		}
+	}
+
+	for _, format := range formats {
+		formatCfg, embedded, err := s.exportFormat(format)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := parseExportTemplate(formatCfg, embedded); err != nil {
+			errs = append(errs, fmt.Errorf("export format %s template %w", format, err))
+		}
+		for _, name := range []string{formatCfg.FileName, formatCfg.ChangelogFileName} {
+			if _, err := template.New("").Parse(name); err != nil {
+				errs = append(errs, fmt.Errorf("export format %s file name %w", format, err))
+			}
+		}
+	}
+	return errs
+}
+
+// CheckRepos checks every configured repo can be read with the current token
+func (s Service) CheckRepos(ctx context.Context) []RepoCheck {
+	repos := s.config.Repos()
+	checks := make([]RepoCheck, 0, len(repos))
+	for _, repo := range repos {
+		_, err := s.gh.GetRepository(ctx, s.config.Github.Org, repo)
+		checks = append(checks, RepoCheck{Repo: repo, Err: err})
+	}
+	return checks
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
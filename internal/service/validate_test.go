@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"github.com/adam-putland/divido-cli/internal/models"
+	util "github.com/adam-putland/divido-cli/internal/util/github"
+	"github.com/adam-putland/divido-cli/internal/util/github/mock"
+	"github.com/google/go-github/v45/github"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestService_CheckRepos(t *testing.T) {
+
+	config := models.Config{
+		Github:    models.GithubConfig{Org: "test"},
+		Platforms: []models.PlatformConfig{{Name: "ing", HelmChartRepo: "ing-platform-hlm"}},
+		Services: models.ServiceRules{
+			{Match: ".*Api", ServiceMapping: models.ServiceMapping{Repo: "missing-api"}},
+			{Match: "^legacy", Ignore: true},
+		},
+	}
+	if err := config.Services.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{
+		gh: &util.GithubClient{
+			Client: github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if strings.HasSuffix(r.URL.Path, "/missing-api") {
+							mock.WriteError(w, http.StatusNotFound, "Not Found")
+							return
+						}
+						w.Write([]byte(`{"name": "ing-platform-hlm"}`))
+					}),
+				))),
+		},
+		config: &config,
+	}
+
+	checks := s.CheckRepos(context.Background())
+	if len(checks) != 2 {
+		t.Fatalf("CheckRepos() got = %v checks, want 2", len(checks))
+	}
+
+	tests := []struct {
+		repo    string
+		wantErr bool
+	}{
+		{repo: "ing-platform-hlm", wantErr: false},
+		{repo: "missing-api", wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			if checks[i].Repo != tt.repo || (checks[i].Err != nil) != tt.wantErr {
+				t.Errorf("CheckRepos() got = %v %v, want %v wantErr %v", checks[i].Repo, checks[i].Err, tt.repo, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ValidateExport(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		export   models.ExportConfig
+		wantErrs int
+	}{
+		{name: "default", export: models.ExportConfig{}, wantErrs: 0},
+		{name: "unknown_format", export: models.ExportConfig{Format: "slack"}, wantErrs: 1},
+		{name: "unknown_archive", export: models.ExportConfig{Archive: "rar"}, wantErrs: 1},
+		{name: "invalid_file_name", export: models.ExportConfig{Formats: map[string]models.ExportFormatConfig{
+			FormatText: {FileName: "{{ .Repo"},
+		}}, wantErrs: 1},
+		{name: "missing_template", export: models.ExportConfig{Formats: map[string]models.ExportFormatConfig{
+			"slack": {Template: "./missing.tmpl", FileName: "release.txt", ChangelogFileName: "{{ .Repo }}.txt"},
+		}}, wantErrs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{config: &models.Config{Export: tt.export}}
+			if got := s.ValidateExport(); len(got) != tt.wantErrs {
+				t.Errorf("ValidateExport() got = %v, want %v errors", got, tt.wantErrs)
+			}
+		})
+	}
+}
@@ -2,4 +2,8 @@ package util
 
 import "github.com/pkg/errors"
 
-var ErrMissingPlat = errors.New("could not get platform")
+var (
+	ErrMissingPlat    = errors.New("could not get platform")
+	ErrIgnoredService = errors.New("service is ignored by the service rules")
+	ErrNotInteractive = errors.New("not running in a terminal")
+)
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+const _defaultSubmitLabel = "Continue"
+
+// Field is a value of a Form, Validate is optional
+type Field struct {
+	Label    string
+	Value    *string
+	Validate func(string) error
+}
+
+// Action is an extra option of a Form, e.g. editing every field at once
+type Action struct {
+	Label string
+	Run   func() error
+}
+
+// Form shows its fields and lets each one be changed until it is submitted with valid values or left with Back
+type Form struct {
+	Title   string
+	Fields  []*Field
+	Actions []Action
+	// Submit is the label of the submit option, defaults to Continue
+	Submit string
+}
+
+// Run returns true when the form is submitted and false when it is left with Back
+func (f Form) Run() (bool, error) {
+	submit := f.Submit
+	if submit == "" {
+		submit = _defaultSubmitLabel
+	}
+
+	options := make(Options, 0, len(f.Fields)+len(f.Actions)+2)
+	for _, field := range f.Fields {
+		options = append(options, "Change "+field.Label)
+	}
+	for _, action := range f.Actions {
+		options = append(options, action.Label)
+	}
+	options = append(options, submit).WithBackOption()
+
+	for {
+		fmt.Print(f)
+
+		index, _, err := Select("Select Option", options)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case index < len(f.Fields):
+			field := f.Fields[index]
+			value, err := PromptWithValidation("Enter "+field.Label, *field.Value, field.Validate)
+			if err != nil {
+				return false, err
+			}
+			*field.Value = value
+
+		case index < len(f.Fields)+len(f.Actions):
+			if err := f.Actions[index-len(f.Fields)].Run(); err != nil {
+				fmt.Println(err)
+			}
+
+		case options[index] == submit:
+			if err := f.Validate(); err != nil {
+				fmt.Println(err)
+				continue
+			}
+			return true, nil
+
+		default:
+			return false, nil
+		}
+	}
+}
+
+// Validate checks every field, all the invalid fields are reported
+func (f Form) Validate() error {
+	var problems []string
+	for _, field := range f.Fields {
+		if field.Validate == nil {
+			continue
+		}
+		if err := field.Validate(*field.Value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", field.Label, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid %s:\n %s", strings.ToLower(f.Title), strings.Join(problems, "\n "))
+	}
+	return nil
+}
+
+func (f Form) String() string {
+	var builder strings.Builder
+	builder.WriteString(f.Title + "\n")
+	for _, field := range f.Fields {
+		value := strings.ReplaceAll(*field.Value, "\n", "\n   ")
+		fmt.Fprintf(&builder, " %s: %s\n", field.Label, value)
+	}
+	return builder.String()
+}
+
+// Required validates the value is not blank
+func Required(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+// Email validates the value is a single email address, without a name
+func Email(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		return errors.New("is not a valid email")
+	}
+	return nil
+}
+
+// NotEqual validates the value differs from other
+func NotEqual(other string) func(string) error {
+	return func(value string) error {
+		if value == other {
+			return fmt.Errorf("cannot be %s", other)
+		}
+		return nil
+	}
+}
+
+// All validates the value with every validator, returning the first error
+func All(validators ...func(string) error) func(string) error {
+	return func(value string) error {
+		for _, validate := range validators {
+			if err := validate(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
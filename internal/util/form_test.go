@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		validate func(string) error
+		value    string
+		wantErr  bool
+	}{
+		{name: "required", validate: Required, value: "chore/bump-hc", wantErr: false},
+		{name: "required_blank", validate: Required, value: "  ", wantErr: true},
+		{name: "email", validate: Email, value: "tech@divido.com", wantErr: false},
+		{name: "email_invalid", validate: Email, value: "tech", wantErr: true},
+		{name: "email_with_name", validate: Email, value: "Tech <tech@divido.com>", wantErr: true},
+		{name: "not_equal", validate: NotEqual("master"), value: "chore/bump-hc", wantErr: false},
+		{name: "equal", validate: NotEqual("master"), value: "master", wantErr: true},
+		{name: "all_first_error", validate: All(Required, NotEqual("master")), value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForm_Validate(t *testing.T) {
+
+	name, email, description := "", "tech", ""
+	form := Form{Title: "Details", Fields: []*Field{
+		{Label: "Author Name", Value: &name, Validate: Required},
+		{Label: "Author Email", Value: &email, Validate: Email},
+		{Label: "Description", Value: &description},
+	}}
+
+	want := "invalid details:\n Author Name: is required\n Author Email: is not a valid email"
+	if err := form.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() got = %v, want %v", err, want)
+	}
+
+	name, email = "jane", "jane@divido.com"
+	if err := form.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,121 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	_scoreMatch       = 16
+	_scoreBoundary    = 8
+	_scoreConsecutive = 10
+	_penaltyGap       = 1
+	// _maxPenaltyLeading caps the penalty of the letters before the first match, so a word at the end still ranks well
+	_maxPenaltyLeading = 3
+)
+
+// Matcher fuzzy matches the search of a prompt against the names of its items, e.g. MultiSelect.Scorer
+type Matcher []string
+
+// Score scores the search against the name of the item at the index
+func (m Matcher) Score(input string, index int) (int, bool) {
+	return FuzzyScore(input, m[index])
+}
+
+// Match reports whether the search matches the name of the item at the index, e.g. MultiSelect.Searcher
+func (m Matcher) Match(input string, index int) bool {
+	_, ok := m.Score(input, index)
+	return ok
+}
+
+// FuzzyScore matches the pattern as a subsequence of the text ignoring case and spaces, so "gql" matches
+// "graphqlApi" and "pwp" matches "portals-web-pub". The best match scores the highest, letters starting a
+// word (after - _ . / or a camelCase hump) and consecutive letters score more than letters far apart
+func FuzzyScore(pattern, text string) (int, bool) {
+	search := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	if len(search) == 0 {
+		return 0, true
+	}
+
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for j, r := range runes {
+		lower[j] = unicode.ToLower(r)
+	}
+	if len(search) > len(lower) {
+		return 0, false
+	}
+
+	// best[j] is the best score of the pattern so far ending with a match at j, noMatch when there is none
+	const noMatch = -1 << 31
+	best := make([]int, len(lower))
+	for j := range lower {
+		best[j] = noMatch
+		if lower[j] == search[0] {
+			best[j] = matchScore(runes, j) - min(_penaltyGap*j, _maxPenaltyLeading)
+		}
+	}
+
+	for i := 1; i < len(search); i++ {
+		next := make([]int, len(lower))
+		// gapped is the best score of an earlier match followed by a gap, less the gap penalty up to j
+		gapped := noMatch
+		for j := range lower {
+			next[j] = noMatch
+			if j > 0 && lower[j] == search[i] {
+				score := noMatch
+				if best[j-1] != noMatch {
+					score = best[j-1] + _scoreConsecutive
+				}
+				if gapped != noMatch && gapped > score {
+					score = gapped
+				}
+				if score != noMatch {
+					next[j] = score + matchScore(runes, j)
+				}
+			}
+
+			if gapped != noMatch {
+				gapped -= _penaltyGap
+			}
+			if j > 0 && best[j-1] != noMatch && best[j-1]-_penaltyGap > gapped {
+				gapped = best[j-1] - _penaltyGap
+			}
+		}
+		best = next
+	}
+
+	score, ok := noMatch, false
+	for _, s := range best {
+		if s > score {
+			score, ok = s, s != noMatch
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	return score, true
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// matchScore scores a matched letter, more when it starts a word
+func matchScore(runes []rune, j int) int {
+	if j == 0 {
+		return _scoreMatch + _scoreBoundary
+	}
+
+	prev, cur := runes[j-1], runes[j]
+	switch {
+	case strings.ContainsRune(" -_./:", prev),
+		unicode.IsLower(prev) && unicode.IsUpper(cur),
+		unicode.IsLetter(prev) != unicode.IsLetter(cur) && !unicode.IsPunct(cur):
+		return _scoreMatch + _scoreBoundary
+	}
+	return _scoreMatch
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    bool
+	}{
+		{name: "empty", pattern: "", text: "application-api", want: true},
+		{name: "substring", pattern: "api", text: "application-api", want: true},
+		{name: "camel_case", pattern: "gql", text: "graphqlApi", want: true},
+		{name: "kebab_case", pattern: "pwp", text: "portals-web-pub", want: true},
+		{name: "case_and_spaces", pattern: "Web Pub", text: "portals-web-pub", want: true},
+		{name: "out_of_order", pattern: "bew", text: "portals-web-pub", want: false},
+		{name: "longer", pattern: "portals-web-pub-x", text: "portals-web-pub", want: false},
+		{name: "missing", pattern: "xyz", text: "application-api", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := FuzzyScore(tt.pattern, tt.text); got != tt.want {
+				t.Errorf("FuzzyScore() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Score(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		pattern string
+		names   Matcher
+		want    []string
+	}{
+		{name: "prefix_first", pattern: "api",
+			names: Matcher{"application-api", "rapid-deploy", "api-gateway"},
+			want:  []string{"api-gateway", "application-api", "rapid-deploy"}},
+		{name: "words_first", pattern: "ga",
+			names: Matcher{"graphql", "graphqlApi", "api-gateway"},
+			want:  []string{"api-gateway", "graphqlApi", "graphql"}},
+		{name: "consecutive_first", pattern: "web",
+			names: Matcher{"w-e-b", "portals-web-pub", "webhooks"},
+			want:  []string{"webhooks", "portals-web-pub", "w-e-b"}},
+		{name: "version", pattern: "1.2",
+			names: Matcher{"v1.12.0", "v2.1.0", "v1.2.0"},
+			want:  []string{"v1.2.0", "v1.12.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			scores := make(map[string]int)
+			for i, name := range tt.names {
+				if score, ok := tt.names.Score(tt.pattern, i); ok {
+					got = append(got, name)
+					scores[name] = score
+				}
+			}
+			sort.SliceStable(got, func(i, j int) bool { return scores[got[i]] > scores[got[j]] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Score() got = %v, want %v (%v)", got, tt.want, scores)
+			}
+		})
+	}
+}
@@ -1,10 +1,31 @@
 package github
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"github.com/adam-putland/divido-cli/internal/models"
+	"gopkg.in/yaml.v3"
+	"strings"
 )
 
+const _commitTemplateHeader = `# Edit the commit details and save the file to apply them, closing it unchanged keeps them.
+# Multi-line values use a YAML block, e.g.
+# message: |
+#   first line
+#   second line
+`
+
+// commitFile is the editable part of a commit, pull request fields are nil for direct commits
+type commitFile struct {
+	AuthorName             string  `yaml:"authorName"`
+	AuthorEmail            string  `yaml:"authorEmail"`
+	Branch                 string  `yaml:"branch"`
+	Message                string  `yaml:"message"`
+	PullRequestTitle       *string `yaml:"pullRequestTitle,omitempty"`
+	PullRequestDescription *string `yaml:"pullRequestDescription,omitempty"`
+}
+
 type Commit struct {
 	PullRequestDescription string
 	PullRequestTitle       string
@@ -41,10 +62,49 @@ func WithBumpServices(config *models.GithubConfig) *Commit {
 	return commit
 }
 
-func (c Commit) String() string {
-	return fmt.Sprintf(" Org: %s\n Author name: %s\n Author email: %s\n Branch: %s\n Commit message: %s", c.Org, c.AuthorName, c.AuthorEmail, c.Branch, c.Message)
+// EditTemplate renders the commit as a commented YAML file, the pull request fields are only included for pull requests
+func (c Commit) EditTemplate(pullRequest bool) ([]byte, error) {
+	file := commitFile{AuthorName: c.AuthorName, AuthorEmail: c.AuthorEmail, Branch: c.Branch, Message: c.Message}
+	if pullRequest {
+		file.PullRequestTitle = &c.PullRequestTitle
+		file.PullRequestDescription = &c.PullRequestDescription
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(_commitTemplateHeader)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-func (c Commit) PullRequestInfo() string {
-	return fmt.Sprintf("\n Pull request title: %s\n Pull request description: %s", c.PullRequestTitle, c.PullRequestDescription)
+// ApplyEdit sets the commit from an edited template, the commit is unchanged when the template is invalid
+func (c *Commit) ApplyEdit(content []byte) error {
+	var file commitFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("reading commit details %w", err)
+	}
+
+	file.Branch = strings.TrimSpace(file.Branch)
+	file.Message = strings.TrimSpace(file.Message)
+	if file.Branch == "" || file.Message == "" {
+		return errors.New("branch and message are required")
+	}
+
+	c.AuthorName = strings.TrimSpace(file.AuthorName)
+	c.AuthorEmail = strings.TrimSpace(file.AuthorEmail)
+	c.Branch = file.Branch
+	c.Message = file.Message
+	if file.PullRequestTitle != nil {
+		c.PullRequestTitle = strings.TrimSpace(*file.PullRequestTitle)
+	}
+	if file.PullRequestDescription != nil {
+		c.PullRequestDescription = strings.TrimRight(*file.PullRequestDescription, "\n")
+	}
+	return nil
 }
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommit_ApplyEdit(t *testing.T) {
+
+	commit := Commit{AuthorName: "dividotech", AuthorEmail: "tech@divido.com", Branch: "chore/bump-hc-v1.2.0",
+		Message: "chore(autocommit): bump hc v1.2.0", PullRequestTitle: "bump hc v1.2.0", Org: "dividohq"}
+
+	tests := []struct {
+		name        string
+		pullRequest bool
+		edit        func(string) string
+		want        Commit
+		wantErr     bool
+	}{
+		{
+			name:        "unchanged",
+			pullRequest: true,
+			edit:        func(s string) string { return s },
+			want:        commit,
+		},
+		{
+			name:        "multi_line_description",
+			pullRequest: true,
+			edit: func(s string) string {
+				s = strings.Replace(s, "branch: chore/bump-hc-v1.2.0", "branch: release/v1.2.0", 1)
+				return strings.Replace(s, `pullRequestDescription: ""`, "pullRequestDescription: |\n  Bumps the helm chart\n\n  - ING-12\n", 1)
+			},
+			want: Commit{AuthorName: "dividotech", AuthorEmail: "tech@divido.com", Branch: "release/v1.2.0",
+				Message: "chore(autocommit): bump hc v1.2.0", PullRequestTitle: "bump hc v1.2.0",
+				PullRequestDescription: "Bumps the helm chart\n\n- ING-12", Org: "dividohq"},
+		},
+		{
+			name:        "direct_commit_without_pull_request",
+			pullRequest: false,
+			edit: func(s string) string {
+				if strings.Contains(s, "pullRequest") {
+					t.Errorf("EditTemplate() got = %s, want no pull request fields", s)
+				}
+				return strings.Replace(s, "authorName: dividotech", "authorName: jane", 1)
+			},
+			want: Commit{AuthorName: "jane", AuthorEmail: "tech@divido.com", Branch: "chore/bump-hc-v1.2.0",
+				Message: "chore(autocommit): bump hc v1.2.0", PullRequestTitle: "bump hc v1.2.0", Org: "dividohq"},
+		},
+		{
+			name:        "missing_message",
+			pullRequest: false,
+			edit: func(s string) string {
+				return strings.Replace(s, "message: 'chore(autocommit): bump hc v1.2.0'", "message: ''", 1)
+			},
+			want:    commit,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := commit.EditTemplate(tt.pullRequest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := commit
+			err = got.ApplyEdit([]byte(tt.edit(string(content))))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyEdit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyEdit() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/adam-putland/divido-cli/internal/models"
 	"github.com/google/go-github/v45/github"
 	"golang.org/x/oauth2"
+	"net/http"
 	"strings"
 	"time"
 )
 
 type GithubClient struct {
 	Client *github.Client
+	// GraphQL fetches many files and releases with a single query, it needs a token
+	GraphQL bool
 }
 
 var (
@@ -26,10 +30,17 @@ func NewGithubClient(ctx context.Context, token string) *GithubClient {
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	return &GithubClient{
-		Client: github.NewClient(tc),
+		Client:  github.NewClient(tc),
+		GraphQL: token != "",
 	}
 }
 
+// IsNotFound reports whether the GitHub API answered the request with a 404
+func IsNotFound(err error) bool {
+	var errResponse *github.ErrorResponse
+	return errors.As(err, &errResponse) && errResponse.Response != nil && errResponse.Response.StatusCode == http.StatusNotFound
+}
+
 func (c GithubClient) GetContent(ctx context.Context, sourceOwner, sourceRepo, filePath, ref string) ([]byte, error) {
 
 	contentFile, _, _, err := c.Client.Repositories.GetContents(ctx, sourceOwner, sourceRepo, filePath, &github.RepositoryContentGetOptions{
@@ -88,7 +99,7 @@ func (c GithubClient) Commit(ctx context.Context, data []byte, sourceOwner strin
 	return err
 }
 
-func (c *GithubClient) GetChangelog(ctx context.Context, org string, repo string, base string, head string) (*github.RepositoryReleaseNotes, error) {
+func (c *GithubClient) GetChangelog(ctx context.Context, org string, repo string, base string, head string) (*models.Changelog, error) {
 	res, _, err := c.Client.Repositories.GenerateReleaseNotes(ctx, org, repo, &github.GenerateNotesOptions{
 		TagName:         head,
 		PreviousTagName: github.String(base),
@@ -97,38 +108,62 @@ func (c *GithubClient) GetChangelog(ctx context.Context, org string, repo string
 		return nil, err
 	}
 
-	if strings.HasPrefix(res.Body, "**Full Changelog**") {
+	changelog := &models.Changelog{
+		Org:  org,
+		Repo: repo,
+		From: base,
+		To:   head,
+	}
+
+	if !strings.HasPrefix(res.Body, "**Full Changelog**") {
+		changelog.Changes = models.ParseReleaseNotes(res.Body)
+		return changelog, nil
+	}
 
-		resp, _, err := c.Client.Repositories.CompareCommits(ctx, org, repo, base, head, nil)
+	// no pull requests between both tags, the changelog is built from the commits instead
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		resp, r, err := c.Client.Repositories.CompareCommits(ctx, org, repo, base, head, opts)
 		if err != nil {
 			return nil, err
 		}
 
-		var builder strings.Builder
-		builder.Grow(len(resp.Commits))
 		for _, commit := range resp.Commits {
-			_, err := fmt.Fprintf(&builder, "%s\n", *commit.Commit.Message)
-			if err != nil {
-				return nil, err
+			change := models.ParseCommit(commit.GetCommit().GetMessage())
+			change.SHA = commit.GetSHA()
+			change.Author = commit.GetAuthor().GetLogin()
+			if change.Author == "" {
+				change.Author = commit.GetCommit().GetAuthor().GetName()
 			}
+			changelog.Changes = append(changelog.Changes, change)
 		}
 
-		return &github.RepositoryReleaseNotes{
-			Name: repo,
-			Body: builder.String(),
-		}, nil
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
 	}
 
-	return res, nil
+	return changelog, nil
 }
 
 func (c *GithubClient) GetReleases(ctx context.Context, org string, repo string) ([]*github.RepositoryRelease, error) {
-	res, _, err := c.Client.Repositories.ListReleases(ctx, org, repo, nil)
-	if err != nil {
-		return nil, err
+	var releases []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		res, r, err := c.Client.Repositories.ListReleases(ctx, org, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, res...)
+
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
 	}
 
-	return res, nil
+	return releases, nil
 }
 
 func (c *GithubClient) GetRelease(ctx context.Context, org string, repo string, version string) (*github.RepositoryRelease, error) {
@@ -225,3 +260,60 @@ func (c GithubClient) CreatePullRequest(ctx context.Context, data []byte,
 	fmt.Printf("pr created at: %s\n", pr.GetHTMLURL())
 	return nil
 }
+
+func (c *GithubClient) GetCommitSHA(ctx context.Context, org string, repo string, ref string) (string, error) {
+	sha, _, err := c.Client.Repositories.GetCommitSHA1(ctx, org, repo, ref, "")
+	if err != nil {
+		return "", err
+	}
+
+	return sha, nil
+}
+
+// GetFileCommits returns the latest commits of the ref changing the file, newest first
+func (c *GithubClient) GetFileCommits(ctx context.Context, org, repo, filePath, ref string, count int) ([]*github.RepositoryCommit, error) {
+	opts := &github.CommitsListOptions{SHA: ref, Path: filePath, ListOptions: github.ListOptions{PerPage: count}}
+	commits, _, err := c.Client.Repositories.ListCommits(ctx, org, repo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+func (c *GithubClient) GetAuthenticatedUser(ctx context.Context) (*github.User, error) {
+	user, _, err := c.Client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (c *GithubClient) GetRepository(ctx context.Context, org string, repo string) (*github.Repository, error) {
+	repository, _, err := c.Client.Repositories.Get(ctx, org, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func (c *GithubClient) ListOrgRepos(ctx context.Context, org string) ([]*github.Repository, error) {
+	var repos []*github.Repository
+	opts := &github.RepositoryListByOrgOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		res, r, err := c.Client.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, res...)
+
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
+	}
+
+	return repos, nil
+}
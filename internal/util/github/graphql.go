@@ -0,0 +1,231 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/adam-putland/divido-cli/internal/util"
+	"github.com/google/go-github/v45/github"
+	"strings"
+	"time"
+)
+
+// _graphqlBatch is the number of repos or files fetched by a single query
+const _graphqlBatch = 50
+
+var (
+	ErrFileNotFound = errors.New("file not found")
+	ErrRepoNotFound = errors.New("repo not found")
+)
+
+// FileRef is a file of a repo at a ref, e.g. a branch or a tag
+type FileRef struct {
+	Repo string
+	Path string
+	Ref  string
+}
+
+type graphqlRequest struct {
+	Query string `json:"query"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
+}
+
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// graphqlBlob is a file, its text is null when it is binary
+type graphqlBlob struct {
+	Text *string `json:"text"`
+}
+
+type graphqlRelease struct {
+	Name         string    `json:"name"`
+	TagName      string    `json:"tagName"`
+	Description  string    `json:"description"`
+	URL          string    `json:"url"`
+	PublishedAt  time.Time `json:"publishedAt"`
+	IsPrerelease bool      `json:"isPrerelease"`
+}
+
+const _graphqlReleaseFields = "name tagName description url publishedAt isPrerelease"
+
+// graphql runs the query against the GraphQL API of the client, the data is decoded into v. A repo not found
+// is not an error, its alias is null
+func (c GithubClient) graphql(ctx context.Context, query string, v interface{}) error {
+	u := *c.Client.BaseURL
+	// GitHub Enterprise serves REST under /api/v3/ and GraphQL under /api/graphql
+	u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+
+	req, err := c.Client.NewRequest("POST", u.String(), graphqlRequest{Query: query})
+	if err != nil {
+		return err
+	}
+
+	var res graphqlResponse
+	if _, err := c.Client.Do(ctx, req, &res); err != nil {
+		return err
+	}
+
+	for _, e := range res.Errors {
+		if e.Type != "NOT_FOUND" {
+			return fmt.Errorf("graphql %s", e.Message)
+		}
+	}
+	if len(res.Data) == 0 {
+		return errors.New("graphql returned no data")
+	}
+	return json.Unmarshal(res.Data, v)
+}
+
+// GetContents fetches many files of the org with a GraphQL query per 50 files, the error of a file is
+// ErrFileNotFound when its repo, ref or path does not exist
+func (c GithubClient) GetContents(ctx context.Context, org string, files []FileRef) ([][]byte, util.Errors, error) {
+	contents := make([][]byte, len(files))
+	errs := make(util.Errors, len(files))
+
+	for start := 0; start < len(files); start += _graphqlBatch {
+		end := start + _graphqlBatch
+		if end > len(files) {
+			end = len(files)
+		}
+
+		var query strings.Builder
+		query.WriteString("query {")
+		for i := start; i < end; i++ {
+			fmt.Fprintf(&query, " f%d: repository(owner: %s, name: %s) { object(expression: %s) { ... on Blob { text } } }",
+				i, quote(org), quote(files[i].Repo), quote(files[i].Ref+":"+files[i].Path))
+		}
+		query.WriteString(" }")
+
+		var data map[string]*struct {
+			Object *graphqlBlob `json:"object"`
+		}
+		if err := c.graphql(ctx, query.String(), &data); err != nil {
+			return nil, nil, err
+		}
+
+		for i := start; i < end; i++ {
+			repo := data[fmt.Sprintf("f%d", i)]
+			switch {
+			case repo == nil || repo.Object == nil || repo.Object.Text == nil:
+				errs[i] = fmt.Errorf("%s/%s@%s %w", files[i].Repo, files[i].Path, files[i].Ref, ErrFileNotFound)
+			default:
+				contents[i] = []byte(*repo.Object.Text)
+			}
+		}
+	}
+	return contents, errs, nil
+}
+
+// GetReposReleases fetches the releases of many repos of the org with a GraphQL query per 50 repos, the repos
+// with more than 100 releases are paginated with REST. The error of a repo is ErrRepoNotFound when it does not exist
+func (c GithubClient) GetReposReleases(ctx context.Context, org string, repos []string) ([][]*github.RepositoryRelease, util.Errors, error) {
+	releases := make([][]*github.RepositoryRelease, len(repos))
+	errs := make(util.Errors, len(repos))
+
+	for start := 0; start < len(repos); start += _graphqlBatch {
+		end := start + _graphqlBatch
+		if end > len(repos) {
+			end = len(repos)
+		}
+
+		var query strings.Builder
+		query.WriteString("query {")
+		for i := start; i < end; i++ {
+			fmt.Fprintf(&query, " r%d: repository(owner: %s, name: %s) { releases(first: 100, orderBy: {field: CREATED_AT, direction: DESC}) { pageInfo { hasNextPage } nodes { %s } } }",
+				i, quote(org), quote(repos[i]), _graphqlReleaseFields)
+		}
+		query.WriteString(" }")
+
+		var data map[string]*struct {
+			Releases struct {
+				PageInfo struct {
+					HasNextPage bool `json:"hasNextPage"`
+				} `json:"pageInfo"`
+				Nodes []graphqlRelease `json:"nodes"`
+			} `json:"releases"`
+		}
+		if err := c.graphql(ctx, query.String(), &data); err != nil {
+			return nil, nil, err
+		}
+
+		for i := start; i < end; i++ {
+			repo := data[fmt.Sprintf("r%d", i)]
+			if repo == nil {
+				errs[i] = fmt.Errorf("%s %w", repos[i], ErrRepoNotFound)
+				continue
+			}
+
+			if repo.Releases.PageInfo.HasNextPage {
+				all, err := c.GetReleases(ctx, org, repos[i])
+				if err != nil {
+					return nil, nil, err
+				}
+				releases[i] = all
+				continue
+			}
+
+			repoReleases := make([]*github.RepositoryRelease, 0, len(repo.Releases.Nodes))
+			for _, node := range repo.Releases.Nodes {
+				repoReleases = append(repoReleases, node.toRelease())
+			}
+			releases[i] = repoReleases
+		}
+	}
+	return releases, errs, nil
+}
+
+// GetLatestReleaseContent fetches the latest release of a repo and a file at its tag with a single GraphQL query
+func (c GithubClient) GetLatestReleaseContent(ctx context.Context, org, repo, filePath string) (*github.RepositoryRelease, []byte, error) {
+	query := fmt.Sprintf("query { repository(owner: %s, name: %s) { latestRelease { %s tagCommit { file(path: %s) { object { ... on Blob { text } } } } } } }",
+		quote(org), quote(repo), _graphqlReleaseFields, quote(filePath))
+
+	var data struct {
+		Repository *struct {
+			LatestRelease *struct {
+				graphqlRelease
+				TagCommit *struct {
+					File *struct {
+						Object *graphqlBlob `json:"object"`
+					} `json:"file"`
+				} `json:"tagCommit"`
+			} `json:"latestRelease"`
+		} `json:"repository"`
+	}
+	if err := c.graphql(ctx, query, &data); err != nil {
+		return nil, nil, err
+	}
+
+	if data.Repository == nil || data.Repository.LatestRelease == nil {
+		return nil, nil, fmt.Errorf("no release found in %s", repo)
+	}
+	latest := data.Repository.LatestRelease
+	if latest.TagCommit == nil || latest.TagCommit.File == nil || latest.TagCommit.File.Object == nil || latest.TagCommit.File.Object.Text == nil {
+		return nil, nil, fmt.Errorf("%s/%s@%s %w", repo, filePath, latest.TagName, ErrFileNotFound)
+	}
+	return latest.toRelease(), []byte(*latest.TagCommit.File.Object.Text), nil
+}
+
+func (r graphqlRelease) toRelease() *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		Name:        github.String(r.Name),
+		TagName:     github.String(r.TagName),
+		Body:        github.String(r.Description),
+		HTMLURL:     github.String(r.URL),
+		PublishedAt: &github.Timestamp{Time: r.PublishedAt},
+		Prerelease:  github.Bool(r.IsPrerelease),
+	}
+}
+
+// quote quotes a GraphQL string, its escapes are the same as JSON
+func quote(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
@@ -0,0 +1,126 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/adam-putland/divido-cli/internal/util/github/mock"
+	"github.com/google/go-github/v45/github"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// graphqlClient answers every GraphQL query with the response, the queries are kept in order
+func graphqlClient(response string, queries *[]string) GithubClient {
+	return GithubClient{
+		Client: github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PostGraphql,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var req graphqlRequest
+					if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+						mock.WriteError(w, http.StatusBadRequest, err.Error())
+						return
+					}
+					*queries = append(*queries, req.Query)
+					w.Write([]byte(response))
+				}),
+			))),
+		GraphQL: true,
+	}
+}
+
+func TestGithubClient_GetContents(t *testing.T) {
+
+	var queries []string
+	c := graphqlClient(`{"data": {"f0": {"object": {"text": "services: {}"}}, "f1": {"object": null}, "f2": null},
+		"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository with the name 'test/missing'."}]}`, &queries)
+
+	files := []FileRef{
+		{Repo: "ing-platform-hlm", Path: "charts/services/values.yaml", Ref: "v1.0.0"},
+		{Repo: "ing-platform-test-inf", Path: "helm/platform/versions.yaml", Ref: "master"},
+		{Repo: "missing", Path: "values.yaml", Ref: "master"},
+	}
+	contents, errs, err := c.GetContents(context.Background(), "test", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := [][]byte{[]byte("services: {}"), nil, nil}; !reflect.DeepEqual(contents, want) {
+		t.Errorf("GetContents() got = %q, want %q", contents, want)
+	}
+	for index, err := range errs {
+		if wantNotFound := index > 0; errors.Is(err, ErrFileNotFound) != wantNotFound {
+			t.Errorf("GetContents() file %d error = %v, want not found %v", index, err, wantNotFound)
+		}
+	}
+
+	if len(queries) != 1 || !strings.Contains(queries[0], `object(expression: "v1.0.0:charts/services/values.yaml")`) {
+		t.Errorf("GetContents() got queries = %v, want a single query of every file", queries)
+	}
+}
+
+func TestGithubClient_GetContentsFailed(t *testing.T) {
+
+	var queries []string
+	c := graphqlClient(`{"data": null, "errors": [{"type": "FORBIDDEN", "message": "Resource not accessible"}]}`, &queries)
+
+	_, _, err := c.GetContents(context.Background(), "test", []FileRef{{Repo: "ing-platform-hlm", Path: "values.yaml", Ref: "master"}})
+	if err == nil {
+		t.Errorf("GetContents() error = %v, want an error", err)
+	}
+}
+
+func TestGithubClient_GetReposReleases(t *testing.T) {
+
+	var queries []string
+	c := graphqlClient(`{"data": {
+		"r0": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": [
+			{"name": "v1.1.0", "tagName": "v1.1.0", "description": "body", "url": "url", "publishedAt": "2022-06-02T00:00:00Z", "isPrerelease": true}
+		]}},
+		"r1": null}}`, &queries)
+
+	got, errs, err := c.GetReposReleases(context.Background(), "test", []string{"application-api", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]*github.RepositoryRelease{
+		{{
+			Name:        github.String("v1.1.0"),
+			TagName:     github.String("v1.1.0"),
+			Body:        github.String("body"),
+			HTMLURL:     github.String("url"),
+			PublishedAt: &github.Timestamp{Time: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC)},
+			Prerelease:  github.Bool(true),
+		}},
+		nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReposReleases() got = %v, want %v", got, want)
+	}
+	if errs[0] != nil || !errors.Is(errs[1], ErrRepoNotFound) {
+		t.Errorf("GetReposReleases() errs = %v, want the missing repo not found", errs)
+	}
+}
+
+func TestGithubClient_GetLatestReleaseContent(t *testing.T) {
+
+	var queries []string
+	c := graphqlClient(`{"data": {"repository": {"latestRelease": {"tagName": "v1.2.0", "publishedAt": "2022-06-02T00:00:00Z",
+		"tagCommit": {"file": {"object": {"text": "services: {}"}}}}}}}`, &queries)
+
+	release, content, err := c.GetLatestReleaseContent(context.Background(), "test", "ing-platform-hlm", "charts/services/values.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.GetTagName() != "v1.2.0" || string(content) != "services: {}" {
+		t.Errorf("GetLatestReleaseContent() got = %v %q, want v1.2.0 services: {}", release.GetTagName(), content)
+	}
+	if len(queries) != 1 {
+		t.Errorf("GetLatestReleaseContent() got = %v queries, want 1", len(queries))
+	}
+}
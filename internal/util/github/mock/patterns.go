@@ -4,3 +4,48 @@ var GetReposReleasesLatestByOwnerByRepo = EndpointPattern{
 	Pattern: "/repos/{owner}/{repo}/releases/latest",
 	Method:  "GET",
 }
+
+var GetReposReleasesByOwnerByRepo = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}/releases",
+	Method:  "GET",
+}
+
+var GetReposByOwnerByRepo = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}",
+	Method:  "GET",
+}
+
+var GetOrgsReposByOrg = EndpointPattern{
+	Pattern: "/orgs/{org}/repos",
+	Method:  "GET",
+}
+
+var GetReposContentsByOwnerByRepoByPath = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}/contents/{path:.+}",
+	Method:  "GET",
+}
+
+var PostGraphql = EndpointPattern{
+	Pattern: "/graphql",
+	Method:  "POST",
+}
+
+var GetReposCommitsByOwnerByRepo = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}/commits",
+	Method:  "GET",
+}
+
+var GetReposCommitsByOwnerByRepoByRef = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}/commits/{ref:.+}",
+	Method:  "GET",
+}
+
+var PostReposReleasesGenerateNotesByOwnerByRepo = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}/releases/generate-notes",
+	Method:  "POST",
+}
+
+var GetReposReleasesTagsByOwnerByRepoByTag = EndpointPattern{
+	Pattern: "/repos/{owner}/{repo}/releases/tags/{tag}",
+	Method:  "GET",
+}
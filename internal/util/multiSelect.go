@@ -2,6 +2,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/chzyer/readline"
 	"github.com/juju/ansiterm"
@@ -9,7 +10,9 @@ import (
 	"github.com/manifoldco/promptui/list"
 	"github.com/manifoldco/promptui/screenbuf"
 	"io"
+	"reflect"
 	"sort"
+	"strings"
 	"text/template"
 )
 
@@ -46,6 +49,18 @@ type MultiSelect struct {
 	// selected is a map used to keep track of selected items - holds their indexes.
 	selected map[int]bool
 
+	// selectedOnly hides the items that are not selected, it is toggled with the SelectedOnly key.
+	selectedOnly bool
+
+	// scope holds the indexes of the items shown, i.e. matching the search and the selected only toggle.
+	scope []int
+
+	// items holds the Items to build the list of the items shown.
+	items reflect.Value
+
+	// single returns the highlighted item on enter instead of the selected ones, it ranks SelectWithSearch.
+	single bool
+
 	// Size is the number of items that should appear on the select before scrolling is necessary. Defaults to 5.
 	Size int
 
@@ -59,6 +74,9 @@ type MultiSelect struct {
 	// HideHelp sets whether to hide help information.
 	HideHelp bool
 
+	// HideCounter sets whether to hide the selection counter, e.g. when the list is only shown.
+	HideCounter bool
+
 	// Templates can be used to customize the select output. If nil is passed, the
 	// default templates are used. See the SelectTemplates docs for more info.
 	Templates *MultiSelectTemplates
@@ -71,13 +89,25 @@ type MultiSelect struct {
 	//
 	// Search is a function that will receive the searched term and the item's index and should return a boolean
 	// for whether or not the terms are alike. It is unimplemented by default and search will not work unless
-	// it is implemented.
+	// it is implemented. Leaving the search mode keeps the items found, the select all, none and invert keys
+	// only change the items shown.
 	Searcher list.Searcher
 
+	// Scorer searches the items like the Searcher and ranks the items found by their score, the best first,
+	// e.g. Matcher.Score. It is used instead of the Searcher when both are set.
+	Scorer func(input string, index int) (int, bool)
+
 	// StartInSearchMode sets whether or not the select mode should start in search mode or selection mode.
 	// For search mode to work, the Search property must be implemented.
 	StartInSearchMode bool
 
+	// Preview loads extra data of the item at the index for the Details template, e.g. from an API. It is called
+	// in the background once per item when the item is first highlighted, so the list stays responsive, and the
+	// Details template receives a PreviewDetails instead of the item. Its context is canceled when the select returns.
+	Preview func(ctx context.Context, index int) (interface{}, error)
+
+	previews *previews
+
 	list *list.List
 
 	// A function that determines how to render the cursor
@@ -107,6 +137,18 @@ type MultiSelectKeys struct {
 
 	// Toggle is the key used to toggle the item selection. Defaults to the space key.
 	Toggle promptui.Key
+
+	// SelectAll is the key used to select every item shown. Defaults to the "a" key.
+	SelectAll promptui.Key
+
+	// SelectNone is the key used to unselect every item shown. Defaults to the "n" key.
+	SelectNone promptui.Key
+
+	// Invert is the key used to invert the selection of the items shown. Defaults to the "i" key.
+	Invert promptui.Key
+
+	// SelectedOnly is the key used to toggle showing only the selected items. Defaults to the "s" key.
+	SelectedOnly promptui.Key
 }
 
 // MultiSelectTemplates allow a select list to be customized following stdlib
@@ -165,6 +207,10 @@ type MultiSelectTemplates struct {
 	// it shows keys for movement and search.
 	Help string
 
+	// Counter is a text/template for the selection counter displayed above the label. It receives
+	// .Selected, .Total, .Shown and .SelectedOnly, by default it shows "N of M selected".
+	Counter string
+
 	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
 	// documentation.
 	//
@@ -179,6 +225,7 @@ type MultiSelectTemplates struct {
 	unselected *template.Template
 	details    *template.Template
 	help       *template.Template
+	counter    *template.Template
 }
 
 // Run executes the select list. It displays the label and the list of items, asking the user to check
@@ -186,7 +233,7 @@ type MultiSelectTemplates struct {
 // the command prompt or selection has finished. It will return the indexes of all the selected items
 // and an error if any occurred during the select's execution.
 func (s *MultiSelect) Run() ([]int, error) {
-	return s.RunCursorAt(s.CursorPos, 0)
+	return _prompter.MultiSelect(s)
 }
 
 // RunCursorAt executes the select list, initializing the cursor to the given
@@ -200,25 +247,29 @@ func (s *MultiSelect) RunCursorAt(cursorPos, scroll int) ([]int, error) {
 		s.Size = 5
 	}
 
-	l, err := list.New(s.Items, s.Size)
-	if err != nil {
-		return nil, err
+	s.items = reflect.ValueOf(s.Items)
+	if s.items.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("items %v is not a slice", s.Items)
 	}
 
 	s.selected = make(map[int]bool)
-	if s.Selected != nil {
-		for _, i := range s.Selected {
+	for _, i := range s.Selected {
+		if i >= 0 && i < s.items.Len() {
 			s.selected[i] = true
 		}
 	}
 
-	l.Searcher = s.Searcher
+	s.previews = nil
+	if s.Preview != nil {
+		s.previews = newPreviews(s.Preview)
+		defer s.previews.close()
+	}
 
-	s.list = l
+	s.filter("")
 
 	s.setKeys()
 
-	err = s.prepareTemplates()
+	err := s.prepareTemplates()
 	if err != nil {
 		return nil, err
 	}
@@ -235,6 +286,11 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 		return nil, err
 	}
 
+	if s.previews != nil {
+		reader := s.previews.reader(c.Stdin)
+		defer reader.Close()
+		c.Stdin = reader
+	}
 	c.Stdin = readline.NewCancelableStdin(c.Stdin)
 
 	if s.IsVimMode {
@@ -254,21 +310,26 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 
 	cur := promptui.NewCursor("", s.Pointer, false)
 
-	canSearch := s.Searcher != nil
+	canSearch := s.Searcher != nil || s.Scorer != nil
 	searchMode := s.StartInSearchMode
 	s.list.SetCursor(cursorPos)
 	s.list.SetStart(scroll)
 
 	c.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
 		switch {
+		case key == _refreshKey:
+			// a preview was loaded, the prompt is rendered again
 		case key == promptui.KeyEnter:
 			return nil, 0, true
 		case key == s.Keys.Next.Code || (key == 'j' && !searchMode):
 			s.list.Next()
 		case key == s.Keys.Prev.Code || (key == 'k' && !searchMode):
 			s.list.Prev()
-		case key == s.Keys.Toggle.Code:
-			idx := s.list.Index()
+		case key == s.Keys.Toggle.Code && !s.single:
+			idx, ok := s.active()
+			if !ok {
+				break
+			}
 			if s.selected[idx] {
 				delete(s.selected, idx)
 			} else {
@@ -280,33 +341,35 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 				break
 			}
 
-			if searchMode {
-				searchMode = false
-				cur.Replace("")
-				s.list.CancelSearch()
-			} else {
-				searchMode = true
-			}
+			// the items found are kept when leaving the search mode, until the search is cleared
+			searchMode = !searchMode
 		case key == promptui.KeyBackspace || key == promptui.KeyCtrlH:
 			if !canSearch || !searchMode {
 				break
 			}
 
 			cur.Backspace()
-			if len(cur.Get()) > 0 {
-				s.list.Search(cur.Get())
-			} else {
-				s.list.CancelSearch()
-			}
+			s.filter(cur.Get())
 		case key == s.Keys.PageUp.Code || (key == 'h' && !searchMode):
 			s.list.PageUp()
 		case key == s.Keys.PageDown.Code || (key == 'l' && !searchMode):
 			s.list.PageDown()
-		default:
-			if canSearch && searchMode {
+		case searchMode:
+			if canSearch {
 				cur.Update(string(line))
-				s.list.Search(cur.Get())
+				s.filter(cur.Get())
 			}
+		case s.single:
+			// the selection keys only apply to multi selects
+		case key == s.Keys.SelectAll.Code:
+			s.selectShown(true)
+		case key == s.Keys.SelectNone.Code:
+			s.selectShown(false)
+		case key == s.Keys.Invert.Code:
+			s.invertShown()
+		case key == s.Keys.SelectedOnly.Code:
+			s.selectedOnly = !s.selectedOnly
+			s.filter(cur.Get())
 		}
 
 		if searchMode {
@@ -317,6 +380,10 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 			sb.Write(help)
 		}
 
+		if !s.HideCounter {
+			sb.Write(s.renderCounter())
+		}
+
 		label := render(s.Templates.label, s.Label)
 		sb.Write(label)
 
@@ -341,18 +408,19 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 
 			output := []byte(page + " ")
 
-			var selectableItem []byte
-
-			if s.selected[s.list.Start()+i] {
-				selectableItem = render(s.Templates.selected, item)
-			} else {
-				selectableItem = render(s.Templates.unselected, item)
+			selectableItem := item
+			switch {
+			case s.single:
+			case s.selected[s.scope[s.list.Start()+i]]:
+				selectableItem = string(render(s.Templates.selected, item))
+			default:
+				selectableItem = string(render(s.Templates.unselected, item))
 			}
 
 			if i == idx {
-				output = append(output, render(s.Templates.active, string(selectableItem))...)
+				output = append(output, render(s.Templates.active, selectableItem)...)
 			} else {
-				output = append(output, render(s.Templates.inactive, string(selectableItem))...)
+				output = append(output, render(s.Templates.inactive, selectableItem)...)
 			}
 
 			sb.Write(output)
@@ -362,7 +430,10 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 			sb.WriteString("")
 			sb.WriteString("No results")
 		} else {
-			active := items[idx]
+			var active interface{} = items[idx]
+			if index, ok := s.active(); ok && s.previews != nil {
+				active = s.previews.get(index, active)
+			}
 
 			details := s.renderDetails(active)
 			for _, d := range details {
@@ -388,8 +459,15 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 			break
 		}
 
-		_, idx := s.list.Items()
-		if idx != list.NotFound && !searchMode {
+		if s.single {
+			// enter picks the highlighted item, also while searching
+			if _, ok := s.active(); ok {
+				break
+			}
+			continue
+		}
+
+		if !searchMode {
 			break
 		}
 
@@ -407,16 +485,23 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 		return nil, err
 	}
 
-	items, idx := s.list.Items()
-	item := items[idx]
-
 	sb.Reset()
-	sb.Write(render(s.Templates.selected, item))
+	if items, idx := s.list.Items(); idx != list.NotFound {
+		sb.Write(render(s.Templates.selected, items[idx]))
+	}
+	if !s.HideCounter {
+		sb.Write(s.renderCounter())
+	}
 	sb.Flush()
 
 	rl.Write([]byte(showCursor))
 	rl.Close()
 
+	if s.single {
+		index, _ := s.active()
+		return []int{index}, nil
+	}
+
 	s.Selected = make([]int, 0, len(s.selected))
 	for i := range s.selected {
 		s.Selected = append(s.Selected, i)
@@ -426,6 +511,75 @@ func (s *MultiSelect) innerRun(cursorPos, scroll int, top rune) ([]int, error) {
 	return s.Selected, err
 }
 
+// match scores the item for the search, it hides the items not selected when showing the selected only.
+func (s *MultiSelect) match(term string, index int) (int, bool) {
+	switch {
+	case s.selectedOnly && !s.selected[index]:
+		return 0, false
+	case term == "":
+		return 0, true
+	case s.Scorer != nil:
+		return s.Scorer(term, index)
+	case s.Searcher != nil:
+		return 0, s.Searcher(term, index)
+	}
+	return 0, true
+}
+
+// filter shows the items matching the term ranked by score, the scope keeps their indexes.
+func (s *MultiSelect) filter(term string) {
+	term = strings.Trim(term, " ")
+
+	scores := make(map[int]int)
+	s.scope = s.scope[:0]
+	for i := 0; i < s.items.Len(); i++ {
+		if score, ok := s.match(term, i); ok {
+			s.scope = append(s.scope, i)
+			scores[i] = score
+		}
+	}
+	sort.SliceStable(s.scope, func(i, j int) bool {
+		return scores[s.scope[i]] > scores[s.scope[j]]
+	})
+
+	shown := make([]interface{}, 0, len(s.scope))
+	for _, i := range s.scope {
+		shown = append(shown, s.items.Index(i).Interface())
+	}
+	// the list is built again as it can only filter the items, not rank them
+	s.list, _ = list.New(shown, s.Size)
+}
+
+// active returns the index of the highlighted item.
+func (s *MultiSelect) active() (int, bool) {
+	if _, idx := s.list.Items(); idx == list.NotFound {
+		return -1, false
+	}
+	return s.scope[s.list.Index()], true
+}
+
+// selectShown selects, or unselects, every item shown.
+func (s *MultiSelect) selectShown(selected bool) {
+	for _, i := range s.scope {
+		if selected {
+			s.selected[i] = true
+		} else {
+			delete(s.selected, i)
+		}
+	}
+}
+
+// invertShown inverts the selection of every item shown.
+func (s *MultiSelect) invertShown() {
+	for _, i := range s.scope {
+		if s.selected[i] {
+			delete(s.selected, i)
+		} else {
+			s.selected[i] = true
+		}
+	}
+}
+
 // ScrollPosition returns the current scroll position.
 func (s *MultiSelect) ScrollPosition() int {
 	return s.list.Start()
@@ -503,10 +657,18 @@ func (s *MultiSelect) prepareTemplates() error {
 		tpls.details = tpl
 	}
 
+	if tpls.Help == "" && s.single {
+		tpls.Help = `{{ "Use the arrow keys to navigate:" | faint }} {{ .NextKey | faint }} ` +
+			`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }} ` +
+			`{{ if .Search }} {{ "and" | faint }} {{ .SearchKey | faint }} {{ "toggles search" | faint }}{{ end }}`
+	}
+
 	if tpls.Help == "" {
 		tpls.Help = fmt.Sprintf(`{{ "Navigate with arrow keys:" | faint }} {{ .NextKey | faint }} ` +
 			`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }}` +
-			`{{ " (" | faint }}{{ .ToggleKey | faint }} {{ "to select)" | faint }}`)
+			`{{ " (" | faint }}{{ .ToggleKey | faint }} {{ "to select," | faint }} ` +
+			`{{ .SelectAllKey | faint }}{{ "/" | faint }}{{ .SelectNoneKey | faint }}{{ "/" | faint }}{{ .InvertKey | faint }} ` +
+			`{{ "all/none/invert," | faint }} {{ .SelectedOnlyKey | faint }} {{ "selected only)" | faint }}`)
 	}
 
 	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Help)
@@ -516,6 +678,19 @@ func (s *MultiSelect) prepareTemplates() error {
 
 	tpls.help = tpl
 
+	if tpls.Counter == "" {
+		tpls.Counter = `{{ print .Selected " of " .Total " selected" | faint }}` +
+			`{{ if .SelectedOnly }}{{ ", showing selected only" | faint }}` +
+			`{{ else if lt .Shown .Total }}{{ print ", " .Shown " shown" | faint }}{{ end }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Counter)
+	if err != nil {
+		return err
+	}
+
+	tpls.counter = tpl
+
 	s.Templates = tpls
 
 	return nil
@@ -532,6 +707,11 @@ func (s *MultiSelect) setKeys() {
 		PageDown: promptui.Key{Code: promptui.KeyForward, Display: promptui.KeyForwardDisplay},
 		Toggle:   promptui.Key{Code: ' ', Display: "SPACE"},
 		Search:   promptui.Key{Code: '/', Display: "/"},
+
+		SelectAll:    promptui.Key{Code: 'a', Display: "a"},
+		SelectNone:   promptui.Key{Code: 'n', Display: "n"},
+		Invert:       promptui.Key{Code: 'i', Display: "i"},
+		SelectedOnly: promptui.Key{Code: 's', Display: "s"},
 	}
 }
 
@@ -557,26 +737,50 @@ func (s *MultiSelect) renderDetails(item interface{}) [][]byte {
 
 func (s *MultiSelect) renderHelp(b bool) []byte {
 	keys := struct {
-		NextKey     string
-		PrevKey     string
-		PageDownKey string
-		PageUpKey   string
-		ToggleKey   string
-		Search      bool
-		SearchKey   string
+		NextKey         string
+		PrevKey         string
+		PageDownKey     string
+		PageUpKey       string
+		ToggleKey       string
+		Search          bool
+		SearchKey       string
+		SelectAllKey    string
+		SelectNoneKey   string
+		InvertKey       string
+		SelectedOnlyKey string
 	}{
-		NextKey:     s.Keys.Next.Display,
-		PrevKey:     s.Keys.Prev.Display,
-		PageDownKey: s.Keys.PageDown.Display,
-		PageUpKey:   s.Keys.PageUp.Display,
-		ToggleKey:   s.Keys.Toggle.Display,
-		SearchKey:   s.Keys.Search.Display,
-		Search:      b,
+		NextKey:         s.Keys.Next.Display,
+		PrevKey:         s.Keys.Prev.Display,
+		PageDownKey:     s.Keys.PageDown.Display,
+		PageUpKey:       s.Keys.PageUp.Display,
+		ToggleKey:       s.Keys.Toggle.Display,
+		SearchKey:       s.Keys.Search.Display,
+		Search:          b,
+		SelectAllKey:    s.Keys.SelectAll.Display,
+		SelectNoneKey:   s.Keys.SelectNone.Display,
+		InvertKey:       s.Keys.Invert.Display,
+		SelectedOnlyKey: s.Keys.SelectedOnly.Display,
 	}
 
 	return render(s.Templates.help, keys)
 }
 
+func (s *MultiSelect) renderCounter() []byte {
+	counter := struct {
+		Selected     int
+		Total        int
+		Shown        int
+		SelectedOnly bool
+	}{
+		Selected:     len(s.selected),
+		Total:        s.items.Len(),
+		Shown:        len(s.scope),
+		SelectedOnly: s.selectedOnly,
+	}
+
+	return render(s.Templates.counter, counter)
+}
+
 func render(tpl *template.Template, data interface{}) []byte {
 	var buf bytes.Buffer
 	err := tpl.Execute(&buf, data)
@@ -0,0 +1,192 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMultiSelect_Run(t *testing.T) {
+
+	items := []string{"application-api", "portals-web-pub", "portals-web-admin", "worker"}
+	searcher := func(input string, index int) bool {
+		return strings.Contains(items[index], input)
+	}
+
+	tests := []struct {
+		name     string
+		keys     string
+		selected []int
+		want     []int
+	}{
+		{name: "toggle", keys: " jj \r", want: []int{0, 2}},
+		{name: "select_all", keys: "a\r", want: []int{0, 1, 2, 3}},
+		{name: "select_none", keys: "n\r", selected: []int{1, 3}, want: []int{}},
+		{name: "invert", keys: "i\r", selected: []int{1, 3}, want: []int{0, 2}},
+		{name: "select_all_found", keys: "/portals/a\r", selected: []int{3}, want: []int{1, 2, 3}},
+		{name: "invert_found", keys: "/portals/i\r", selected: []int{1, 3}, want: []int{2, 3}},
+		{name: "toggle_found", keys: "/admin/ \r", want: []int{2}},
+		{name: "selected_only", keys: "sj \r", selected: []int{1, 3}, want: []int{1}},
+		{name: "out_of_range_selected", keys: "\r", selected: []int{1, 7}, want: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MultiSelect{
+				Label:    "Select Services to update",
+				Items:    items,
+				Selected: tt.selected,
+				Searcher: searcher,
+				Stdin:    io.NopCloser(strings.NewReader(tt.keys)),
+				Stdout:   nopWriteCloser{&bytes.Buffer{}},
+			}
+			got, err := s.RunCursorAt(0, 0)
+			if err != nil {
+				t.Fatalf("RunCursorAt() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RunCursorAt() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiSelect_Scorer(t *testing.T) {
+
+	items := Matcher{"application-api", "rapid-deploy", "api-gateway"}
+
+	tests := []struct {
+		name string
+		keys string
+		want []int
+	}{
+		{name: "best_first", keys: "/api/ \r", want: []int{2}},
+		{name: "second", keys: "/api/j \r", want: []int{0}},
+		{name: "select_all_found", keys: "/gw/a\r", want: []int{2}},
+		{name: "not_searching", keys: " \r", want: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MultiSelect{
+				Label:  "Select Services to update",
+				Items:  items,
+				Scorer: items.Score,
+				Stdin:  io.NopCloser(strings.NewReader(tt.keys)),
+				Stdout: nopWriteCloser{&bytes.Buffer{}},
+			}
+			got, err := s.RunCursorAt(0, 0)
+			if err != nil {
+				t.Fatalf("RunCursorAt() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RunCursorAt() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// syncBuffer is written by the prompt while the test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Close() error { return nil }
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestMultiSelect_Preview(t *testing.T) {
+
+	items := []string{"application-api", "portals-web-pub"}
+	loads := make(chan int, len(items))
+	contexts := make(chan context.Context, len(items))
+	release := make(chan struct{})
+
+	stdin, keys := io.Pipe()
+	out := &syncBuffer{}
+	s := &MultiSelect{
+		Label: "Services:",
+		Items: items,
+		Templates: &MultiSelectTemplates{
+			Details: `{{ if .Loading }}loading {{ .Item }}{{ else }}{{ .Item }} latest {{ .Preview }}{{ end }}`,
+		},
+		Preview: func(ctx context.Context, index int) (interface{}, error) {
+			loads <- index
+			contexts <- ctx
+			<-release
+			return fmt.Sprintf("v1.%d.0", index), nil
+		},
+		Stdin:  stdin,
+		Stdout: out,
+	}
+
+	type result struct {
+		selected []int
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		selected, err := s.RunCursorAt(0, 0)
+		done <- result{selected, err}
+	}()
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(out.String(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("RunCursorAt() output does not contain %q", want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// each highlighted item is loaded in the background
+	for _, want := range []int{0, 1} {
+		if index := <-loads; index != want {
+			t.Errorf("Preview() got = %v, want %v", index, want)
+		}
+		if want == 0 {
+			keys.Write([]byte("j"))
+		}
+	}
+	waitFor("loading portals-web-pub")
+
+	// the loaded preview is rendered without any key pressed
+	close(release)
+	waitFor("portals-web-pub latest v1.1.0")
+
+	keys.Write([]byte(" \r"))
+	got := <-done
+	if got.err != nil {
+		t.Fatalf("RunCursorAt() error = %v", got.err)
+	}
+	if !reflect.DeepEqual(got.selected, []int{1}) {
+		t.Errorf("RunCursorAt() got = %v, want %v", got.selected, []int{1})
+	}
+
+	// the loads are canceled and the next key is left to the next prompt
+	if ctx := <-contexts; ctx.Err() == nil {
+		t.Errorf("Preview() context is not canceled once the select returned")
+	}
+	go keys.Write([]byte("x"))
+	next := make([]byte, 1)
+	if _, err := inputOf(stdin).Read(next); err != nil || string(next) != "x" {
+		t.Errorf("Read() got = %q %v, want the key after the select", next, err)
+	}
+}
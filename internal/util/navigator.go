@@ -0,0 +1,120 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"github.com/manifoldco/promptui"
+	"strings"
+)
+
+const (
+	_backOption = "Back"
+	_homeOption = "Home"
+	_exitOption = "Exit"
+
+	_breadcrumbSeparator = " > "
+)
+
+// Screen is a menu of the interactive cli. Run shows it once and moves the navigator, the screen is
+// shown again when it does not move it, so Run must always prompt
+type Screen interface {
+	// Title is the breadcrumb of the screen, e.g. "Helm: ing"
+	Title() string
+	Run(nav *Navigator) error
+}
+
+// Navigator keeps the stack of open screens, Ctrl+C on a menu goes back one level and
+// Ctrl+C on a prompt opened from a menu cancels it back to the menu
+type Navigator struct {
+	stack []Screen
+}
+
+func NewNavigator(home Screen) *Navigator {
+	return &Navigator{stack: []Screen{home}}
+}
+
+// Run shows the screen on top of the stack until the stack is empty, errors are printed and the screen is shown again
+func (n *Navigator) Run() error {
+	for len(n.stack) > 0 {
+		screen := n.stack[len(n.stack)-1]
+		fmt.Println(promptui.Styler(promptui.FGFaint)(n.Breadcrumbs()))
+
+		err := screen.Run(n)
+		switch {
+		case err == nil, errors.Is(err, promptui.ErrInterrupt):
+		case errors.Is(err, promptui.ErrEOF):
+			return nil
+		case errors.Is(err, ErrNotInteractive):
+			return err
+		default:
+			fmt.Println(err)
+		}
+	}
+	return nil
+}
+
+// Push opens a screen on top of the current one
+func (n *Navigator) Push(screen Screen) {
+	n.stack = append(n.stack, screen)
+}
+
+// Back closes the current screen, closing the home screen exits
+func (n *Navigator) Back() {
+	if len(n.stack) > 0 {
+		n.stack = n.stack[:len(n.stack)-1]
+	}
+}
+
+// Home closes every screen but the home screen
+func (n *Navigator) Home() {
+	if len(n.stack) > 1 {
+		n.stack = n.stack[:1]
+	}
+}
+
+// Exit closes every screen
+func (n *Navigator) Exit() {
+	n.stack = nil
+}
+
+func (n *Navigator) Depth() int {
+	return len(n.stack)
+}
+
+func (n *Navigator) Breadcrumbs() string {
+	titles := make([]string, 0, len(n.stack))
+	for _, screen := range n.stack {
+		titles = append(titles, screen.Title())
+	}
+	return strings.Join(titles, _breadcrumbSeparator)
+}
+
+// Menu selects one of the options, followed by Back (Exit on the home screen) and Home. The index is -1
+// when the navigator moved instead, i.e. Back, Home or Ctrl+C were selected
+func (n *Navigator) Menu(label string, options Options) (int, error) {
+	items := append(Options{}, options...)
+	if n.Depth() > 1 {
+		items = append(items, _backOption, _homeOption)
+	} else {
+		items = append(items, _exitOption)
+	}
+
+	index, _, err := Select(label, items)
+	if errors.Is(err, promptui.ErrInterrupt) {
+		n.Back()
+		return -1, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+
+	switch {
+	case index < len(options):
+		return index, nil
+	case items[index] == _homeOption:
+		n.Home()
+	default:
+		n.Back()
+	}
+	return -1, nil
+}
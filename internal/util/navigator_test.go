@@ -0,0 +1,58 @@
+package util
+
+import (
+	"fmt"
+	"github.com/manifoldco/promptui"
+	"testing"
+)
+
+// scriptedScreen moves the navigator with the next step each time it is shown
+type scriptedScreen struct {
+	title string
+	steps []func(nav *Navigator) error
+	shown *[]string
+}
+
+func (s *scriptedScreen) Title() string {
+	return s.title
+}
+
+func (s *scriptedScreen) Run(nav *Navigator) error {
+	*s.shown = append(*s.shown, nav.Breadcrumbs())
+	step := s.steps[0]
+	s.steps = s.steps[1:]
+	return step(nav)
+}
+
+func TestNavigator_Run(t *testing.T) {
+
+	var shown []string
+	env := &scriptedScreen{title: "Env: ing/test", shown: &shown, steps: []func(nav *Navigator) error{
+		func(nav *Navigator) error { return fmt.Errorf("prompt failed: %w", promptui.ErrInterrupt) },
+		func(nav *Navigator) error { return fmt.Errorf("loading env") },
+		func(nav *Navigator) error { nav.Home(); return nil },
+	}}
+	helm := &scriptedScreen{title: "Helm: ing", shown: &shown, steps: []func(nav *Navigator) error{
+		func(nav *Navigator) error { nav.Push(env); return nil },
+	}}
+	home := &scriptedScreen{title: "Home", shown: &shown, steps: []func(nav *Navigator) error{
+		func(nav *Navigator) error { nav.Push(helm); return nil },
+		func(nav *Navigator) error { nav.Back(); return nil },
+	}}
+
+	if err := NewNavigator(home).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Home",
+		"Home > Helm: ing",
+		"Home > Helm: ing > Env: ing/test",
+		"Home > Helm: ing > Env: ing/test",
+		"Home > Helm: ing > Env: ing/test",
+		"Home",
+	}
+	if fmt.Sprint(shown) != fmt.Sprint(want) {
+		t.Errorf("Run() got = %v, want %v", shown, want)
+	}
+}
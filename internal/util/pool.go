@@ -0,0 +1,153 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	_defaultConcurrency = 4
+	_progressWidth      = 30
+)
+
+// Pool runs tasks on a bounded number of goroutines, e.g. to fetch the releases of many services from GitHub
+type Pool struct {
+	// Concurrency is the number of tasks running at once, it defaults to 4
+	Concurrency int
+	// Progress reports the finished tasks, nil reports nothing
+	Progress *Progress
+}
+
+// Errors holds the error of each task by index, nil when the task succeeded
+type Errors []error
+
+// Err returns an error listing the failed tasks, nil when every task succeeded
+func (e Errors) Err() error {
+	var messages []string
+	for _, err := range e {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	switch len(messages) {
+	case 0:
+		return nil
+	case 1:
+		return e.first()
+	}
+	return fmt.Errorf("%d of %d failed:\n %s", len(messages), len(e), strings.Join(messages, "\n "))
+}
+
+func (e Errors) first() error {
+	for _, err := range e {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run runs the task of each index from 0 to n, a failed task does not stop the others. Once the context is
+// canceled the tasks not started yet fail with the context error
+func (p Pool) Run(ctx context.Context, n int, task func(ctx context.Context, index int) error) Errors {
+	errs := make(Errors, n)
+	if n == 0 {
+		return errs
+	}
+
+	concurrency := p.Concurrency
+	if concurrency <= 0 {
+		concurrency = _defaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	p.Progress.start(n)
+	defer p.Progress.finish()
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if err := ctx.Err(); err != nil {
+					errs[index] = err
+				} else {
+					errs[index] = task(ctx, index)
+				}
+				p.Progress.done()
+			}
+		}()
+	}
+
+	for index := 0; index < n; index++ {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// Progress draws a progress bar of the finished tasks, e.g. "Obtaining versions [=====     ] 3/6"
+type Progress struct {
+	Label string
+	Out   io.Writer
+
+	mu       sync.Mutex
+	total    int
+	finished int
+}
+
+// NewProgress draws on the output of the default prompter, nothing is drawn when it is not a terminal
+func NewProgress(label string) *Progress {
+	if !_prompter.Progress {
+		return nil
+	}
+	return &Progress{Label: label, Out: _prompter.Stdout}
+}
+
+func (p *Progress) start(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.total, p.finished = total, 0
+	p.draw()
+}
+
+func (p *Progress) done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finished++
+	p.draw()
+}
+
+func (p *Progress) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintln(p.Out)
+}
+
+// draw redraws the bar over the current line
+func (p *Progress) draw() {
+	filled := _progressWidth * p.finished / p.total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", _progressWidth-filled)
+	fmt.Fprintf(p.Out, "\r%s [%s] %d/%d", p.Label, bar, p.finished, p.total)
+}
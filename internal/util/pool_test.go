@@ -0,0 +1,118 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPool_Run(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		concurrency int
+		n           int
+		fail        map[int]bool
+		wantErr     string
+	}{
+		{name: "empty", concurrency: 2, n: 0},
+		{name: "default_concurrency", n: 10},
+		{name: "bounded", concurrency: 3, n: 10},
+		{name: "single_error", concurrency: 2, n: 5, fail: map[int]bool{3: true}, wantErr: "task 3 failed"},
+		{name: "many_errors", concurrency: 2, n: 5, fail: map[int]bool{1: true, 4: true},
+			wantErr: "2 of 5 failed:\n task 1 failed\n task 4 failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			ran := make([]bool, tt.n)
+
+			pool := Pool{Concurrency: tt.concurrency}
+			errs := pool.Run(context.Background(), tt.n, func(ctx context.Context, index int) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				ran[index] = true
+				mu.Unlock()
+
+				defer func() {
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}()
+				if tt.fail[index] {
+					return fmt.Errorf("task %d failed", index)
+				}
+				return nil
+			})
+
+			for index, ok := range ran {
+				if !ok {
+					t.Errorf("Run() task %d did not run", index)
+				}
+			}
+
+			concurrency := tt.concurrency
+			if concurrency == 0 {
+				concurrency = _defaultConcurrency
+			}
+			if maxRunning > concurrency {
+				t.Errorf("Run() got = %v tasks at once, want at most %v", maxRunning, concurrency)
+			}
+
+			for index, err := range errs {
+				if (err != nil) != tt.fail[index] {
+					t.Errorf("Run() task %d error = %v, want failed %v", index, err, tt.fail[index])
+				}
+			}
+
+			err := errs.Err()
+			if (err != nil) != (tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("Err() got = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPool_RunCanceled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := Pool{Concurrency: 1}
+	errs := pool.Run(ctx, 3, func(ctx context.Context, index int) error {
+		cancel()
+		return nil
+	})
+
+	want := []error{nil, context.Canceled, context.Canceled}
+	for index, err := range errs {
+		if !errors.Is(err, want[index]) {
+			t.Errorf("Run() task %d error = %v, want %v", index, err, want[index])
+		}
+	}
+}
+
+func TestPool_RunProgress(t *testing.T) {
+
+	var out bytes.Buffer
+	pool := Pool{Concurrency: 1, Progress: &Progress{Label: "Fetching", Out: &out}}
+	pool.Run(context.Background(), 2, func(ctx context.Context, index int) error {
+		return nil
+	})
+
+	lines := strings.Split(out.String(), "\r")
+	got := lines[len(lines)-1]
+	want := "Fetching [" + strings.Repeat("=", _progressWidth) + "] 2/2\n"
+	if got != want {
+		t.Errorf("Progress got = %q, want %q", got, want)
+	}
+	if !strings.Contains(out.String(), "] 1/2") {
+		t.Errorf("Progress got = %q, want the bar redrawn on each task", out.String())
+	}
+}
@@ -0,0 +1,221 @@
+package util
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// _refreshKey is read by a prompt when a preview is loaded so it is rendered again, it is a private use rune
+// no terminal sends
+const _refreshKey rune = '\uE000'
+
+// Preview previews the highlighted item of a select below the list, Load is called in the background once per
+// item, its context is canceled when the select returns, and Details is the template receiving its PreviewDetails
+type Preview struct {
+	Load    func(ctx context.Context, index int) (interface{}, error)
+	Details string
+}
+
+// PreviewDetails is given to the Details template of a MultiSelect with a Preview, Preview is set once loaded
+type PreviewDetails struct {
+	Item    interface{}
+	Preview interface{}
+	Loading bool
+	Err     error
+}
+
+// previews loads the previews of the items in the background, each item is loaded once. The loads are canceled
+// by close
+type previews struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	load    func(ctx context.Context, index int) (interface{}, error)
+	mu      sync.Mutex
+	loaded  map[int]*PreviewDetails
+	refresh chan struct{}
+}
+
+func newPreviews(load func(ctx context.Context, index int) (interface{}, error)) *previews {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &previews{
+		ctx:     ctx,
+		cancel:  cancel,
+		load:    load,
+		loaded:  make(map[int]*PreviewDetails),
+		refresh: make(chan struct{}, 1),
+	}
+}
+
+// get returns the preview of the item, it starts loading it the first time
+func (p *previews) get(index int, item interface{}) PreviewDetails {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if details, ok := p.loaded[index]; ok {
+		return *details
+	}
+
+	details := &PreviewDetails{Item: item, Loading: true}
+	p.loaded[index] = details
+	go func() {
+		preview, err := p.load(p.ctx, index)
+
+		p.mu.Lock()
+		details.Preview, details.Err, details.Loading = preview, err, false
+		p.mu.Unlock()
+
+		select {
+		case p.refresh <- struct{}{}:
+		default:
+		}
+	}()
+	return *details
+}
+
+// close cancels the loads still running
+func (p *previews) close() {
+	p.cancel()
+}
+
+// reader returns the input of the prompt, it reads a _refreshKey whenever a preview is loaded
+func (p *previews) reader(stdin io.Reader) io.ReadCloser {
+	return &refreshReader{input: inputOf(stdin), refresh: p.refresh, done: make(chan struct{})}
+}
+
+type backgroundRead struct {
+	data []byte
+	err  error
+}
+
+var (
+	_inputsMu sync.Mutex
+	// _inputs are the inputs read by the prompts keyed by the reader they wrap, e.g. os.Stdin
+	_inputs = make(map[io.Reader]*backgroundInput)
+)
+
+// backgroundInput is an input that can be read in the background, a read still running when its prompt returns
+// is kept for the next prompt reading the input so no key is lost
+type backgroundInput struct {
+	stdin   io.Reader
+	mu      sync.Mutex
+	pending chan backgroundRead
+	rest    []byte
+}
+
+// inputOf returns the input wrapping the reader, every prompt reading the reader must read it through the input
+func inputOf(stdin io.Reader) *backgroundInput {
+	if input, ok := stdin.(*backgroundInput); ok {
+		return input
+	}
+
+	_inputsMu.Lock()
+	defer _inputsMu.Unlock()
+	input, ok := _inputs[stdin]
+	if !ok {
+		input = &backgroundInput{stdin: stdin}
+		_inputs[stdin] = input
+	}
+	return input
+}
+
+// Read returns the data left by a background read first, otherwise it reads the input
+func (in *backgroundInput) Read(b []byte) (int, error) {
+	in.mu.Lock()
+	if len(in.rest) > 0 {
+		n := copy(b, in.rest)
+		in.rest = in.rest[n:]
+		in.mu.Unlock()
+		return n, nil
+	}
+	pending := in.pending
+	in.mu.Unlock()
+
+	if pending == nil {
+		return in.stdin.Read(b)
+	}
+	return in.receive(<-pending, b)
+}
+
+// buffered reports whether data of a previous background read is left
+func (in *backgroundInput) buffered() bool {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return len(in.rest) > 0
+}
+
+// background starts reading the input in the background unless a read is already running, the returned channel
+// receives the read, which must be passed to receive
+func (in *backgroundInput) background(size int) <-chan backgroundRead {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.pending == nil {
+		pending := make(chan backgroundRead, 1)
+		in.pending = pending
+		go func() {
+			data := make([]byte, size)
+			n, err := in.stdin.Read(data)
+			pending <- backgroundRead{data: data[:n], err: err}
+		}()
+	}
+	return in.pending
+}
+
+func (in *backgroundInput) receive(read backgroundRead, b []byte) (int, error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.pending = nil
+	n := copy(b, read.data)
+	in.rest = append(in.rest, read.data[n:]...)
+	return n, read.err
+}
+
+// Close leaves the input open, it is closed by the caller of the prompt
+func (in *backgroundInput) Close() error {
+	return nil
+}
+
+// refreshReader reads the input in the background so a refresh can be read while waiting for a key, once closed
+// its reads return io.EOF and a key read meanwhile is left to the next prompt
+type refreshReader struct {
+	input     *backgroundInput
+	refresh   <-chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	rest      []byte
+}
+
+func (r *refreshReader) Read(b []byte) (int, error) {
+	if len(r.rest) > 0 {
+		n := copy(b, r.rest)
+		r.rest = r.rest[n:]
+		return n, nil
+	}
+	if r.input.buffered() {
+		return r.input.Read(b)
+	}
+
+	select {
+	case <-r.done:
+		return 0, io.EOF
+	default:
+	}
+
+	select {
+	case read := <-r.input.background(len(b)):
+		return r.input.receive(read, b)
+	case <-r.refresh:
+		r.rest = []byte(string(_refreshKey))
+		return r.Read(b)
+	case <-r.done:
+		return 0, io.EOF
+	}
+}
+
+// Close stops reading, the input itself is closed by the caller of the prompt
+func (r *refreshReader) Close() error {
+	r.closeOnce.Do(func() { close(r.done) })
+	return nil
+}
@@ -0,0 +1,347 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+)
+
+const (
+	_defaultAnswersFlag = "--answers file.yaml"
+	_addLabel           = "Other"
+)
+
+// Answer is the answer given to a prompt, an empty answer keeps the default value of a text prompt.
+// Selected has the indexes chosen in a multi select
+type Answer struct {
+	Prompt   string `yaml:"prompt"`
+	Answer   string `yaml:"answer,omitempty"`
+	Selected []int  `yaml:"selected,omitempty"`
+}
+
+// AnswersFile is a recorded interactive session that can be replayed with --answers
+type AnswersFile struct {
+	Answers []Answer `yaml:"answers"`
+}
+
+// Prompter asks every prompt of the cli. Answers are replayed in order before asking on the terminal,
+// without a terminal a prompt with no answer left fails with the flag that provides it
+type Prompter struct {
+	Stdin  io.ReadCloser
+	Stdout io.WriteCloser
+	// Interactive is false when stdin is not a terminal
+	Interactive bool
+	// Progress is false when stdout is not a terminal, e.g. logs get no progress bars
+	Progress bool
+
+	answers   []Answer
+	recording bool
+	recorded  []Answer
+	flags     map[string]string
+}
+
+var _prompter = NewPrompter()
+
+// NewPrompter prompts on the standard input and output
+func NewPrompter() *Prompter {
+	return &Prompter{
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Interactive: isTerminal(os.Stdin),
+		Progress:    isTerminal(os.Stdout),
+		flags:       make(map[string]string),
+	}
+}
+
+// DefaultPrompter returns the prompter used by the prompt functions of this package
+func DefaultPrompter() *Prompter {
+	return _prompter
+}
+
+// SetPrompter replaces the prompter used by the prompt functions of this package
+func SetPrompter(p *Prompter) {
+	_prompter = p
+}
+
+// isTerminal checks the file is a terminal, a char device such as /dev/null is not
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Replay answers the next prompts with the answers file
+func (p *Prompter) Replay(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file AnswersFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("reading answers %w", err)
+	}
+	p.answers = append(p.answers, file.Answers...)
+	return nil
+}
+
+// Record keeps every answer given so the session can be saved with SaveRecording
+func (p *Prompter) Record() {
+	p.recording = true
+}
+
+func (p *Prompter) SaveRecording(path string) error {
+	content, err := yaml.Marshal(AnswersFile{Answers: p.recorded})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// input is the input of the prompts, a key read in the background by a prompt that returned is read by the next one
+func (p *Prompter) input() io.ReadCloser {
+	return inputOf(p.Stdin)
+}
+
+// Flag sets the non-interactive flag suggested when the prompt cannot be asked, it defaults to --answers.
+// Without a terminal a multi select with a flag is answered by the items preselected with the flag
+func (p *Prompter) Flag(prompt, flag string) {
+	p.flags[prompt] = flag
+}
+
+// next returns the answer of the prompt when replaying, it fails when the prompt cannot be asked on the terminal
+func (p *Prompter) next(prompt string) (*Answer, error) {
+	if len(p.answers) > 0 {
+		answer := p.answers[0]
+		if answer.Prompt != prompt {
+			return nil, fmt.Errorf("answers expect prompt %q, got %q", answer.Prompt, prompt)
+		}
+		p.answers = p.answers[1:]
+		return &answer, nil
+	}
+
+	if !p.Interactive {
+		flag, ok := p.flags[prompt]
+		if !ok {
+			flag = _defaultAnswersFlag
+		}
+		return nil, fmt.Errorf("%w: %q needs a terminal, use %s", ErrNotInteractive, prompt, flag)
+	}
+	return nil, nil
+}
+
+func (p *Prompter) record(answer Answer) {
+	if p.recording {
+		p.recorded = append(p.recorded, answer)
+	}
+}
+
+// Select selects one of the items, the search of the items found by the scorer is ranked by score when it is set
+func (p *Prompter) Select(label string, items Options, scorer func(input string, index int) (int, bool)) (int, string, error) {
+	return p.SelectWithLabels(label, items, items, scorer)
+}
+
+// SelectWithLabels selects one of the items showing their labels instead, the answers are the items
+func (p *Prompter) SelectWithLabels(label string, items, labels Options, scorer func(input string, index int) (int, bool)) (int, string, error) {
+	answer, err := p.next(label)
+	if err != nil {
+		return -1, "", err
+	}
+
+	if answer != nil {
+		for i, item := range items {
+			if item == answer.Answer {
+				p.record(*answer)
+				return i, item, nil
+			}
+		}
+		return -1, "", fmt.Errorf("answer %q is not an option of %q", answer.Answer, label)
+	}
+
+	fmt.Fprintln(p.Stdout)
+	if scorer != nil {
+		prompt := MultiSelect{
+			Label:       label,
+			Items:       labels,
+			Scorer:      scorer,
+			HideCounter: true,
+			Stdin:       p.input(),
+			Stdout:      p.Stdout,
+			single:      true,
+		}
+		selected, err := prompt.RunCursorAt(0, 0)
+		if err != nil {
+			return -1, "", err
+		}
+		p.record(Answer{Prompt: label, Answer: items[selected[0]]})
+		return selected[0], items[selected[0]], nil
+	}
+
+	prompt := promptui.Select{
+		Label:  label,
+		Items:  labels,
+		Stdin:  p.input(),
+		Stdout: p.Stdout,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return index, "", err
+	}
+	p.record(Answer{Prompt: label, Answer: items[index]})
+	return index, items[index], nil
+}
+
+// addOption is an option of SelectWithAdd shown by its label, Item is empty on the add option
+type addOption struct {
+	Label string
+	Item  string
+}
+
+func (o addOption) String() string {
+	return o.Label
+}
+
+// SelectWithAdd returns promptui.SelectedAdd as index when a value that is not an option is given, the items
+// are shown with their labels when set and the highlighted item is previewed when the preview is set. The
+// Details template of the preview receives the option as .Item, with its .Label and its .Item
+// (empty on the add option)
+func (p *Prompter) SelectWithAdd(label string, items, labels Options, preview *Preview) (int, string, error) {
+	if labels == nil {
+		labels = items
+	}
+
+	answer, err := p.next(label)
+	if err != nil {
+		return -1, "", err
+	}
+
+	if answer != nil {
+		p.record(*answer)
+		for i, item := range items {
+			if item == answer.Answer {
+				return i, item, nil
+			}
+		}
+		return promptui.SelectedAdd, answer.Answer, nil
+	}
+
+	fmt.Fprintln(p.Stdout)
+	fmt.Fprintln(p.Stdout)
+	if len(items) > 0 {
+		options := append(Options{_addLabel}, labels...)
+		addOptions := make([]addOption, 0, len(options))
+		addOptions = append(addOptions, addOption{Label: _addLabel})
+		for i, item := range items {
+			addOptions = append(addOptions, addOption{Label: labels[i], Item: item})
+		}
+		prompt := MultiSelect{
+			Label:       label,
+			Items:       addOptions,
+			Scorer:      Matcher(options).Score,
+			HideCounter: true,
+			Stdin:       p.input(),
+			Stdout:      p.Stdout,
+			single:      true,
+		}
+		if preview != nil {
+			// the add option has no preview
+			prompt.Preview = func(ctx context.Context, index int) (interface{}, error) {
+				if index == 0 {
+					return nil, nil
+				}
+				return preview.Load(ctx, index-1)
+			}
+			prompt.Templates = &MultiSelectTemplates{Details: preview.Details}
+		}
+
+		selected, err := prompt.RunCursorAt(1, 0)
+		if err != nil {
+			return -1, "", err
+		}
+		if index := selected[0]; index > 0 {
+			p.record(Answer{Prompt: label, Answer: items[index-1]})
+			return index - 1, items[index-1], nil
+		}
+	}
+
+	prompt := promptui.Prompt{Label: _addLabel, Stdin: p.input(), Stdout: p.Stdout}
+	value, err := prompt.Run()
+	if err == nil {
+		p.record(Answer{Prompt: label, Answer: value})
+	}
+	return promptui.SelectedAdd, value, err
+}
+
+func (p *Prompter) Prompt(label, defaultValue string, validate func(string) error) (string, error) {
+	if validate == nil {
+		validate = func(input string) error { return nil }
+	}
+
+	answer, err := p.next(label)
+	if err != nil {
+		return "", err
+	}
+
+	if answer != nil {
+		if answer.Answer == "" {
+			answer.Answer = defaultValue
+		}
+		if err := validate(answer.Answer); err != nil {
+			return "", fmt.Errorf("answer of %q %w", label, err)
+		}
+		p.record(*answer)
+		return answer.Answer, nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:    label,
+		Validate: validate,
+		Default:  defaultValue,
+		Stdin:    p.input(),
+		Stdout:   p.Stdout,
+	}
+	value, err := prompt.Run()
+	if err == nil {
+		p.record(Answer{Prompt: label, Answer: value})
+	}
+	return value, err
+}
+
+func (p *Prompter) MultiSelect(s *MultiSelect) ([]int, error) {
+	label := fmt.Sprint(s.Label)
+	answer, err := p.next(label)
+	if errors.Is(err, ErrNotInteractive) && p.flags[label] != "" && len(s.Selected) > 0 {
+		// the items preselected by the flag of the prompt are the answer, e.g. --services
+		p.record(Answer{Prompt: label, Selected: s.Selected})
+		return s.Selected, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if answer != nil {
+		for _, index := range answer.Selected {
+			if index < 0 || index >= reflect.ValueOf(s.Items).Len() {
+				return nil, fmt.Errorf("answer of %q selects %d, out of range", label, index)
+			}
+		}
+		p.record(*answer)
+		return answer.Selected, nil
+	}
+
+	if s.Stdin == nil {
+		s.Stdin = p.input()
+	}
+	if s.Stdout == nil {
+		s.Stdout = p.Stdout
+	}
+	selected, err := s.RunCursorAt(s.CursorPos, 0)
+	if err == nil {
+		p.record(Answer{Prompt: label, Selected: selected})
+	}
+	return selected, err
+}
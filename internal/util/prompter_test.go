@@ -0,0 +1,234 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func newTestPrompter(input string, answers ...Answer) *Prompter {
+	return &Prompter{
+		Stdin:       io.NopCloser(strings.NewReader(input)),
+		Stdout:      nopWriteCloser{&bytes.Buffer{}},
+		Interactive: input != "",
+		answers:     answers,
+		flags:       map[string]string{"GitHub organisation": "--org", "Select services": "--services"},
+	}
+}
+
+func TestPrompter_Replay(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		answer  Answer
+		run     func(p *Prompter) (interface{}, error)
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "select", answer: Answer{Prompt: "Select platform", Answer: "ing"},
+			run: func(p *Prompter) (interface{}, error) {
+				index, _, err := p.Select("Select platform", Options{"divido", "ing"}, nil)
+				return index, err
+			}, want: 1},
+		{name: "select_unknown_option", answer: Answer{Prompt: "Select platform", Answer: "nordea"},
+			run: func(p *Prompter) (interface{}, error) {
+				index, _, err := p.Select("Select platform", Options{"divido", "ing"}, nil)
+				return index, err
+			}, want: -1, wantErr: true},
+		{name: "other_prompt", answer: Answer{Prompt: "Select env", Answer: "test"},
+			run: func(p *Prompter) (interface{}, error) {
+				index, _, err := p.Select("Select platform", Options{"divido", "ing"}, nil)
+				return index, err
+			}, want: -1, wantErr: true},
+		{name: "select_with_add", answer: Answer{Prompt: "api current (v1.0.0)", Answer: "v1.2.0-rc.1"},
+			run: func(p *Prompter) (interface{}, error) {
+				_, value, err := p.SelectWithAdd("api current (v1.0.0)", Options{"v1.1.0"}, nil, nil)
+				return value, err
+			}, want: "v1.2.0-rc.1"},
+		{name: "prompt_default", answer: Answer{Prompt: "Enter Branch"},
+			run: func(p *Prompter) (interface{}, error) {
+				return p.Prompt("Enter Branch", "master", Required)
+			}, want: "master"},
+		{name: "prompt_invalid", answer: Answer{Prompt: "Enter Author Email", Answer: "tech"},
+			run: func(p *Prompter) (interface{}, error) {
+				return p.Prompt("Enter Author Email", "", Email)
+			}, want: "", wantErr: true},
+		{name: "multi_select", answer: Answer{Prompt: "Select Services to update", Selected: []int{0, 2}},
+			run: func(p *Prompter) (interface{}, error) {
+				return p.MultiSelect(&MultiSelect{Label: "Select Services to update", Items: []string{"api", "portal", "worker"}})
+			}, want: []int{0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.run(newTestPrompter("", tt.answer))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("run() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrompter_SelectWithSearch(t *testing.T) {
+
+	items := Options{"v1.12.0", "v2.1.0", "v1.2.0"}
+
+	tests := []struct {
+		name      string
+		keys      string
+		wantIndex int
+		want      string
+	}{
+		{name: "highlighted", keys: "j\r", wantIndex: 1, want: "v2.1.0"},
+		{name: "best_match", keys: "/1.2\r", wantIndex: 2, want: "v1.2.0"},
+		{name: "second_match", keys: "/1.2/j\r", wantIndex: 0, want: "v1.12.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPrompter(tt.keys)
+			p.Record()
+			index, got, err := p.Select("Select first version", items, Matcher(items).Score)
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if index != tt.wantIndex || got != tt.want {
+				t.Errorf("Select() got = %v %v, want %v %v", index, got, tt.wantIndex, tt.want)
+			}
+			if recorded := p.recorded; len(recorded) != 1 || recorded[0].Answer != tt.want {
+				t.Errorf("Select() recorded = %v, want %v", recorded, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrompter_SelectWithLabels(t *testing.T) {
+
+	items := Options{"v1.2.0", "v1.1.0"}
+	labels := Options{"v1.2.0 (latest)", "v1.1.0 (deployed)"}
+
+	tests := []struct {
+		name   string
+		keys   string
+		answer []Answer
+		want   string
+	}{
+		{name: "search_label", keys: "/deployed\r", want: "v1.1.0"},
+		{name: "replay_item", answer: []Answer{{Prompt: "Select version", Answer: "v1.2.0"}}, want: "v1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPrompter(tt.keys, tt.answer...)
+			p.Record()
+			_, got, err := p.SelectWithLabels("Select version", items, labels, Matcher(labels).Score)
+			if err != nil {
+				t.Fatalf("SelectWithLabels() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SelectWithLabels() got = %v, want %v", got, tt.want)
+			}
+			if recorded := p.recorded; len(recorded) != 1 || recorded[0].Answer != tt.want {
+				t.Errorf("SelectWithLabels() recorded = %v, want %v", recorded, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrompter_SelectWithAddPreview(t *testing.T) {
+
+	out := &bytes.Buffer{}
+	p := newTestPrompter("\r")
+	p.Stdout = nopWriteCloser{out}
+
+	preview := &Preview{
+		Load: func(ctx context.Context, index int) (interface{}, error) {
+			return "changes", nil
+		},
+		Details: `{{ with .Item.Item }}changes to {{ . }}{{ end }}`,
+	}
+	index, got, err := p.SelectWithAdd("api current (v1.0.0)", Options{"v1.1.0"}, Options{"v1.1.0 (latest)"}, preview)
+	if err != nil || index != 0 || got != "v1.1.0" {
+		t.Fatalf("SelectWithAdd() got = %v %v %v, want 0 v1.1.0", index, got, err)
+	}
+	if !strings.Contains(out.String(), "changes to v1.1.0") {
+		t.Errorf("SelectWithAdd() output = %q, want the highlighted version previewed", out.String())
+	}
+}
+
+func TestPrompter_NotInteractive(t *testing.T) {
+
+	p := newTestPrompter("")
+	_, err := p.Prompt("GitHub organisation", "", nil)
+	if !errors.Is(err, ErrNotInteractive) || !strings.Contains(err.Error(), "--org") {
+		t.Errorf("Prompt() error = %v, want ErrNotInteractive suggesting --org", err)
+	}
+
+	_, _, err = p.Select("Select platform", Options{"ing"}, nil)
+	if !errors.Is(err, ErrNotInteractive) || !strings.Contains(err.Error(), "--answers") {
+		t.Errorf("Select() error = %v, want ErrNotInteractive suggesting --answers", err)
+	}
+
+	_, err = p.MultiSelect(&MultiSelect{Label: "Select services", Items: Options{"api", "portal"}})
+	if !errors.Is(err, ErrNotInteractive) || !strings.Contains(err.Error(), "--services") {
+		t.Errorf("MultiSelect() error = %v, want ErrNotInteractive suggesting --services", err)
+	}
+
+	selected, err := p.MultiSelect(&MultiSelect{Label: "Select services", Items: Options{"api", "portal"}, Selected: []int{1}})
+	if err != nil || !reflect.DeepEqual(selected, []int{1}) {
+		t.Errorf("MultiSelect() got = %v %v, want the preselected [1]", selected, err)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+
+	null, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+
+	if isTerminal(null) {
+		t.Errorf("isTerminal() got = true for %s, want false", os.DevNull)
+	}
+}
+
+func TestPrompter_Record(t *testing.T) {
+
+	p := newTestPrompter("dividohq\n")
+	p.Record()
+
+	org, err := p.Prompt("GitHub organisation", "", nil)
+	if err != nil || org != "dividohq" {
+		t.Fatalf("Prompt() got = %v %v, want dividohq", org, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	if err := p.SaveRecording(path); err != nil {
+		t.Fatal(err)
+	}
+
+	replay := newTestPrompter("")
+	if err := replay.Replay(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := replay.Prompt("GitHub organisation", "", nil); err != nil || got != "dividohq" {
+		t.Errorf("Replay() got = %v %v, want dividohq", got, err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+}
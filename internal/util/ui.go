@@ -1,10 +1,5 @@
 package util
 
-import (
-	"fmt"
-	"github.com/manifoldco/promptui"
-)
-
 type Options []string
 
 func (options Options) WithBackOption() Options {
@@ -12,51 +7,33 @@ func (options Options) WithBackOption() Options {
 }
 
 func Select(msg string, elems Options) (int, string, error) {
-	fmt.Println()
-	prompt2 := promptui.Select{
+	return _prompter.Select(msg, elems, nil)
+}
 
-		Label: msg,
-		Items: elems,
-	}
-	return prompt2.Run()
+// SelectWithSearch selects one of the elems, the search is fuzzy and ranks the elems found
+func SelectWithSearch(msg string, elems Options) (int, string, error) {
+	return _prompter.Select(msg, elems, Matcher(elems).Score)
 }
 
-func SelectWithSearch(msg string, elems Options, searcher func(input string, index int) bool) (int, string, error) {
-	fmt.Println()
-	prompt2 := promptui.Select{
-		Label:    msg,
-		Items:    elems,
-		Searcher: searcher,
-	}
-	return prompt2.Run()
+// SelectWithLabels selects one of the elems showing their labels instead, e.g. versions marked as deployed,
+// the search is fuzzy and ranks the labels found
+func SelectWithLabels(msg string, elems, labels Options) (int, string, error) {
+	return _prompter.SelectWithLabels(msg, elems, labels, Matcher(labels).Score)
 }
 
-func SelectWithAdd(msg string, elems Options) (int, string, error) {
-	fmt.Println()
-	fmt.Println()
-	prompt2 := promptui.SelectWithAdd{
-		Label:    msg,
-		Items:    elems,
-		AddLabel: "Other",
-	}
-	return prompt2.Run()
+// SelectWithAdd selects one of the elems, shown with their labels and previewed when set, or a new value
+func SelectWithAdd(msg string, elems, labels Options, preview *Preview) (int, string, error) {
+	return _prompter.SelectWithAdd(msg, elems, labels, preview)
 }
 
 func Prompt(msg string) (string, error) {
-	prompt := promptui.Prompt{
-		Label:    msg,
-		Validate: func(input string) error { return nil },
-	}
-
-	return prompt.Run()
+	return _prompter.Prompt(msg, "", nil)
 }
 
 func PromptWithDefault(msg, defaultMsg string) (string, error) {
-	prompt := promptui.Prompt{
-		Label:    msg,
-		Validate: func(input string) error { return nil },
-		Default:  defaultMsg,
-	}
+	return _prompter.Prompt(msg, defaultMsg, nil)
+}
 
-	return prompt.Run()
+func PromptWithValidation(msg, defaultMsg string, validate func(string) error) (string, error) {
+	return _prompter.Prompt(msg, defaultMsg, validate)
 }